@@ -0,0 +1,225 @@
+package cpm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"thaiqr-go/internal/qr"
+)
+
+// PayloadFormatIndicator is the value of tag 85 for EMVCo Consumer-Presented Mode version 01
+const PayloadFormatIndicator = "CPV01"
+
+// CPM is an EMVCo Consumer-Presented Mode QR, shown by the payer's wallet
+// and scanned by the merchant. Binary values are kept as upper case hex.
+type CPM struct {
+	PayloadFormatIndicator string        // 85; Mandatory, "CPV01"
+	Applications           []Application // 61; Mandatory, at least one
+	CommonData             *Application  // 62; data shared by every application
+	Other                  []*TLV        // unknown top level data objects, kept as found
+}
+
+// Application is an Application Template (61) or the Common Data Template (62)
+type Application struct {
+	ADFName                   string // 4F; AID, hex
+	Label                     string // 50; Application Label
+	Track2                    string // 57; Track 2 Equivalent Data, hex
+	PAN                       string // 5A; Application PAN, digits
+	CardholderName            string // 5F20
+	LanguagePreference        string // 5F2D; such as "enth"
+	ApplicationVersion        string // 9F08; hex
+	IssuerApplicationData     string // 9F10; hex
+	ApplicationCryptogram     string // 9F26; hex
+	CryptogramInformationData string // 9F27; hex
+	ATC                       string // 9F36; Application Transaction Counter, hex
+	UnpredictableNumber       string // 9F37; hex
+	Other                     []*TLV // unknown data objects, kept as found
+}
+
+// Tags of the Consumer-Presented Mode data objects
+const (
+	TagPayloadFormatIndicator    = "85"
+	TagApplicationTemplate       = "61"
+	TagCommonDataTemplate        = "62"
+	TagADFName                   = "4F"
+	TagLabel                     = "50"
+	TagTrack2                    = "57"
+	TagPAN                       = "5A"
+	TagCardholderName            = "5F20"
+	TagLanguagePreference        = "5F2D"
+	TagApplicationVersion        = "9F08"
+	TagIssuerApplicationData     = "9F10"
+	TagApplicationCryptogram     = "9F26"
+	TagCryptogramInformationData = "9F27"
+	TagATC                       = "9F36"
+	TagUnpredictableNumber       = "9F37"
+)
+
+// field is how a tag of an Application is stored
+type field struct {
+	tag  string
+	kind int // kindHex, kindText or kindPAN
+	get  func(a *Application) *string
+}
+
+const (
+	kindHex = iota
+	kindText
+	kindPAN
+)
+
+// fields lists the known tags of an Application in encoding order
+var fields = []field{
+	{TagADFName, kindHex, func(a *Application) *string { return &a.ADFName }},
+	{TagLabel, kindText, func(a *Application) *string { return &a.Label }},
+	{TagTrack2, kindHex, func(a *Application) *string { return &a.Track2 }},
+	{TagPAN, kindPAN, func(a *Application) *string { return &a.PAN }},
+	{TagCardholderName, kindText, func(a *Application) *string { return &a.CardholderName }},
+	{TagLanguagePreference, kindText, func(a *Application) *string { return &a.LanguagePreference }},
+	{TagApplicationVersion, kindHex, func(a *Application) *string { return &a.ApplicationVersion }},
+	{TagIssuerApplicationData, kindHex, func(a *Application) *string { return &a.IssuerApplicationData }},
+	{TagApplicationCryptogram, kindHex, func(a *Application) *string { return &a.ApplicationCryptogram }},
+	{TagCryptogramInformationData, kindHex, func(a *Application) *string { return &a.CryptogramInformationData }},
+	{TagATC, kindHex, func(a *Application) *string { return &a.ATC }},
+	{TagUnpredictableNumber, kindHex, func(a *Application) *string { return &a.UnpredictableNumber }},
+}
+
+func fieldFor(tag string) *field {
+	for i := range fields {
+		if fields[i].tag == tag {
+			return &fields[i]
+		}
+	}
+	return nil
+}
+
+// Decode decodes a base64 Consumer-Presented Mode QR. Errors are returned as *qr.ParseError
+// with offsets in the decoded bytes.
+func Decode(s string) (*CPM, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, &qr.ParseError{Offset: -1, Expected: "base64", Err: qr.ErrBadValue}
+	}
+	return DecodeBytes(b)
+}
+
+// DecodeBytes decodes the BER-TLV bytes of a Consumer-Presented Mode QR
+func DecodeBytes(b []byte) (*CPM, error) {
+	objects, err := ParseTLV(b, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(objects) == 0 || objects[0].Tag != TagPayloadFormatIndicator {
+		return nil, &qr.ParseError{Offset: 0, Path: TagPayloadFormatIndicator, Err: qr.ErrMissingTag}
+	}
+	c := &CPM{}
+	for _, t := range objects {
+		switch t.Tag {
+		case TagPayloadFormatIndicator:
+			c.PayloadFormatIndicator = string(t.Value)
+		case TagApplicationTemplate:
+			c.Applications = append(c.Applications, decodeApplication(t))
+		case TagCommonDataTemplate:
+			a := decodeApplication(t)
+			c.CommonData = &a
+		default:
+			c.Other = append(c.Other, t)
+		}
+	}
+	if c.PayloadFormatIndicator != PayloadFormatIndicator {
+		return nil, &qr.ParseError{Offset: 0, Path: TagPayloadFormatIndicator, Expected: PayloadFormatIndicator, Actual: c.PayloadFormatIndicator, Err: qr.ErrBadValue}
+	}
+	if len(c.Applications) == 0 {
+		return nil, &qr.ParseError{Offset: len(b), Path: TagApplicationTemplate, Err: qr.ErrMissingTag}
+	}
+	return c, nil
+}
+
+func decodeApplication(t *TLV) Application {
+	var a Application
+	for _, c := range t.Children {
+		f := fieldFor(c.Tag)
+		if f == nil {
+			a.Other = append(a.Other, c)
+			continue
+		}
+		switch f.kind {
+		case kindText:
+			*f.get(&a) = string(c.Value)
+		case kindPAN: // compressed numeric, padded with F
+			*f.get(&a) = strings.TrimRight(strings.ToUpper(hex.EncodeToString(c.Value)), "F")
+		default:
+			*f.get(&a) = strings.ToUpper(hex.EncodeToString(c.Value))
+		}
+	}
+	return a
+}
+
+// Encode serializes c into base64 BER-TLV. An empty PayloadFormatIndicator is written as "CPV01".
+func Encode(c *CPM) (string, error) {
+	b, err := EncodeBytes(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+// EncodeBytes serializes c into BER-TLV
+func EncodeBytes(c *CPM) ([]byte, error) {
+	pfi := c.PayloadFormatIndicator
+	if pfi == "" {
+		pfi = PayloadFormatIndicator
+	}
+	objects := []*TLV{{Tag: TagPayloadFormatIndicator, Value: []byte(pfi)}}
+	for i := range c.Applications {
+		t, err := encodeApplication(TagApplicationTemplate, &c.Applications[i])
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, t)
+	}
+	if c.CommonData != nil {
+		t, err := encodeApplication(TagCommonDataTemplate, c.CommonData)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, t)
+	}
+	objects = append(objects, c.Other...)
+	var buf bytes.Buffer
+	if err := writeTLV(&buf, objects); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeApplication(tag string, a *Application) (*TLV, error) {
+	t := &TLV{Tag: tag}
+	for _, f := range fields {
+		v := *f.get(a)
+		if v == "" {
+			continue
+		}
+		var value []byte
+		switch f.kind {
+		case kindText:
+			value = []byte(v)
+		case kindPAN:
+			if len(v)%2 == 1 {
+				v += "F"
+			}
+			fallthrough
+		default:
+			b, err := hex.DecodeString(v)
+			if err != nil {
+				return nil, &qr.ParseError{Offset: -1, Path: tag + "." + f.tag, Expected: "hex", Actual: v, Err: qr.ErrBadValue}
+			}
+			value = b
+		}
+		t.Children = append(t.Children, &TLV{Tag: f.tag, Value: value})
+	}
+	t.Children = append(t.Children, a.Other...)
+	return t, nil
+}
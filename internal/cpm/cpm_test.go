@@ -0,0 +1,171 @@
+package cpm
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+
+	"thaiqr-go/internal/qr"
+)
+
+// specExample is the Consumer-Presented Mode example of EMVCo QRCPS Annex A: two
+// applications and a Common Data Template holding the PAN, the cardholder name,
+// the language preference and an Application Specific Transparent Template (64)
+const specExample = "hQVDUFYwMWETTwegAAAAVVVVUAhQcm9kdWN0MWETTwegAAAAZmZmUAhQcm9kdWN0MmJJWggSNFZ4kBI0WF8gDkNBUkRIT0xERVIvRU1WXy0IcnVlbmRlZnJkIZ8QBwYBCgMAAACfJghYT9OF+iNLzJ82AgABnzcEbVjvEw=="
+
+func TestDecodeSpecExample(t *testing.T) {
+	c, err := Decode(specExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.PayloadFormatIndicator != PayloadFormatIndicator {
+		t.Errorf("PayloadFormatIndicator = %q", c.PayloadFormatIndicator)
+	}
+	want := []Application{
+		{ADFName: "A0000000555555", Label: "Product1"},
+		{ADFName: "A0000000666666", Label: "Product2"},
+	}
+	if !reflect.DeepEqual(c.Applications, want) {
+		t.Errorf("Applications = %+v, want %+v", c.Applications, want)
+	}
+	common := c.CommonData
+	if common == nil {
+		t.Fatal("CommonData is nil")
+	}
+	if common.PAN != "1234567890123458" || common.CardholderName != "CARDHOLDER/EMV" || common.LanguagePreference != "ruendefr" {
+		t.Errorf("CommonData = %+v", common)
+	}
+	if len(common.Other) != 1 || common.Other[0].Tag != "64" {
+		t.Fatalf("CommonData.Other = %+v, want the template 64", common.Other)
+	}
+	tmpl := common.Other[0]
+	if ac := tmpl.Get(TagApplicationCryptogram); ac == nil || hex.EncodeToString(ac.Value) != "584fd385fa234bcc" {
+		t.Errorf("64.9F26 = %+v", ac)
+	}
+	if atc := tmpl.Get(TagATC); atc == nil || atc.Offset != 112 {
+		t.Errorf("64.9F36 = %+v, want offset 112", atc)
+	}
+
+	// Encode writes back the same bytes
+	s, err := Encode(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != specExample {
+		t.Errorf("Encode = %s\nwant     %s", s, specExample)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []*CPM{
+		{Applications: []Application{{ADFName: "A0000000031010", PAN: "4111111111111111"}}},
+		{
+			Applications: []Application{{
+				ADFName:                   "A0000000041010",
+				Label:                     "MASTERCARD",
+				Track2:                    "5413330089010434D2512201",
+				PAN:                       "541333008901043", // odd length, padded with F
+				CardholderName:            "SOMCHAI/JAIDEE",
+				LanguagePreference:        "then",
+				ApplicationVersion:        "0002",
+				IssuerApplicationData:     "0110A00000000000",
+				ApplicationCryptogram:     "1122334455667788",
+				CryptogramInformationData: "80",
+				ATC:                       "0001",
+				UnpredictableNumber:       "DEADBEEF",
+			}},
+			CommonData: &Application{LanguagePreference: "th"},
+		},
+		{
+			PayloadFormatIndicator: PayloadFormatIndicator,
+			Applications: []Application{
+				{ADFName: "A000000677010111", Label: "PromptPay"},
+				{ADFName: "A0000000031010", Other: []*TLV{{Tag: "9F24", Value: []byte{1, 2, 3}}}},
+			},
+			Other: []*TLV{{Tag: "99", Value: []byte("x")}},
+		},
+	}
+	for i, c := range tests {
+		s, err := Encode(c)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		got, err := Decode(s)
+		if err != nil {
+			t.Fatalf("%d: Decode(%s): %v", i, s, err)
+		}
+		again, err := Encode(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if again != s {
+			t.Errorf("%d: encoded %s, then %s", i, s, again)
+		}
+		if got.Applications[0].PAN != c.Applications[0].PAN || got.Applications[0].Track2 != c.Applications[0].Track2 {
+			t.Errorf("%d: got %+v, want %+v", i, got.Applications[0], c.Applications[0])
+		}
+	}
+}
+
+func TestEncodeBadHex(t *testing.T) {
+	_, err := Encode(&CPM{Applications: []Application{{ADFName: "A00000000Z"}}})
+	var pe *qr.ParseError
+	if !errors.As(err, &pe) || !errors.Is(err, qr.ErrBadValue) || pe.Path != "61.4F" {
+		t.Errorf("got %v, want a bad value at 61.4F", err)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	// 85 05 "CPV01" followed by the data under test
+	pfi := "8505435056303" + "1"
+	tests := []struct {
+		name   string
+		hex    string
+		err    error
+		offset int
+		path   string
+	}{
+		{"empty", "", qr.ErrMissingTag, 0, "85"},
+		{"application first", "61054F03A00000" + pfi, qr.ErrMissingTag, 0, "85"},
+		{"no application", pfi, qr.ErrMissingTag, 7, "61"},
+		{"wrong version", "850543505630326105" + "4F03A00000", qr.ErrBadValue, 0, "85"},
+		{"value past the end", pfi + "61064F03A00000", qr.ErrTruncated, 7, "61"},
+		{"child past its template", pfi + "61054F04A0000000", qr.ErrTruncated, 9, "61.4F"},
+		{"missing length", pfi + "61", qr.ErrTruncated, 8, "61"},
+		{"indefinite length", pfi + "6180", qr.ErrBadLength, 8, "61"},
+		{"4 byte length", pfi + "618400000001", qr.ErrBadLength, 8, "61"},
+		{"long length past the end", pfi + "6182", qr.ErrTruncated, 8, "61"},
+		{"truncated tag", pfi + "61019F", qr.ErrTruncated, 9, "61"},
+		{"tag over 4 bytes", pfi + "61069FFFFFFFFF01", qr.ErrBadTag, 9, "61"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := hex.DecodeString(tt.hex)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = Decode(base64.StdEncoding.EncodeToString(b))
+			var pe *qr.ParseError
+			if !errors.As(err, &pe) || !errors.Is(err, tt.err) {
+				t.Fatalf("got %v, want %v", err, tt.err)
+			}
+			if pe.Offset != tt.offset || pe.Path != tt.path {
+				t.Errorf("got offset %d path %q, want %d %q", pe.Offset, pe.Path, tt.offset, tt.path)
+			}
+		})
+	}
+}
+
+func TestDecodeBadBase64(t *testing.T) {
+	for _, s := range []string{"hQVDUFYwMW!!", "hQVDUFYwMWE", "not base64 at all"} {
+		if _, err := Decode(s); !errors.Is(err, qr.ErrBadValue) {
+			t.Errorf("Decode(%q) = %v, want %v", s, err, qr.ErrBadValue)
+		}
+	}
+	// surrounding white space is ignored
+	if _, err := Decode("  " + specExample + "\n"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,158 @@
+package cpm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"thaiqr-go/internal/qr"
+)
+
+// TLV is a single BER-TLV data object
+type TLV struct {
+	Tag      string // upper case hex such as "9F26"
+	Value    []byte // raw value; empty for constructed objects with Children
+	Offset   int    // byte offset of the tag in the decoded payload
+	Children []*TLV // nested data objects when the tag is constructed
+}
+
+// Constructed reports whether the tag holds nested data objects (bit 6 of its first byte)
+func (t *TLV) Constructed() bool {
+	b, err := hex.DecodeString(t.Tag[:2])
+	return err == nil && b[0]&0x20 != 0
+}
+
+// Get returns the first child with the given tag
+func (t *TLV) Get(tag string) *TLV {
+	for _, c := range t.Children {
+		if c.Tag == tag {
+			return c
+		}
+	}
+	return nil
+}
+
+// ParseTLV reads consecutive BER-TLV data objects from b, constructed objects
+// are parsed recursively. base is the offset of b in the payload and parent
+// the tag path of the enclosing template.
+func ParseTLV(b []byte, base int, parent string) ([]*TLV, error) {
+	var objects []*TLV
+	for i := 0; i < len(b); {
+		if b[i] == 0x00 || b[i] == 0xFF { // padding between data objects
+			i++
+			continue
+		}
+		start := i
+		tag, n, err := readTag(b[i:])
+		path := joinPath(parent, tag)
+		if err != nil {
+			return nil, &qr.ParseError{Offset: base + i, Path: parent, Err: err}
+		}
+		i += n
+		l, n, err := readLength(b[i:])
+		if err != nil {
+			return nil, &qr.ParseError{Offset: base + i, Path: path, Err: err}
+		}
+		i += n
+		if l > len(b)-i {
+			return nil, &qr.ParseError{Offset: base + start, Path: path, Expected: fmt.Sprint(l), Actual: fmt.Sprint(len(b) - i), Err: qr.ErrTruncated}
+		}
+		t := &TLV{Tag: tag, Offset: base + start}
+		if t.Constructed() {
+			if t.Children, err = ParseTLV(b[i:i+l], base+i, path); err != nil {
+				return nil, err
+			}
+		} else {
+			t.Value = b[i : i+l]
+		}
+		objects = append(objects, t)
+		i += l
+	}
+	return objects, nil
+}
+
+// readTag returns the hex tag at the start of b and its size in bytes
+func readTag(b []byte) (string, int, error) {
+	n := 1
+	if b[0]&0x1F == 0x1F { // subsequent bytes follow while bit 8 is set
+		for {
+			if n >= len(b) {
+				return "", 0, qr.ErrTruncated
+			}
+			n++
+			if b[n-1]&0x80 == 0 {
+				break
+			}
+			if n > 4 {
+				return "", 0, qr.ErrBadTag
+			}
+		}
+	}
+	return strings.ToUpper(hex.EncodeToString(b[:n])), n, nil
+}
+
+// readLength returns the BER length at the start of b and its size in bytes
+func readLength(b []byte) (int, int, error) {
+	if len(b) == 0 {
+		return 0, 0, qr.ErrTruncated
+	}
+	if b[0] < 0x80 {
+		return int(b[0]), 1, nil
+	}
+	n := int(b[0] & 0x7F)
+	if n == 0 || n > 3 {
+		return 0, 0, qr.ErrBadLength
+	}
+	if len(b) < 1+n {
+		return 0, 0, qr.ErrTruncated
+	}
+	l := 0
+	for _, c := range b[1 : 1+n] {
+		l = l<<8 | int(c)
+	}
+	return l, 1 + n, nil
+}
+
+// writeTLV appends the data objects to buf, constructed objects are written from their Children
+func writeTLV(buf *bytes.Buffer, objects []*TLV) error {
+	for _, t := range objects {
+		tag, err := hex.DecodeString(t.Tag)
+		if err != nil || len(tag) == 0 {
+			return &qr.ParseError{Offset: -1, Path: t.Tag, Expected: "hex tag", Actual: t.Tag, Err: qr.ErrBadTag}
+		}
+		value := t.Value
+		if t.Constructed() {
+			var inner bytes.Buffer
+			if err := writeTLV(&inner, t.Children); err != nil {
+				return err
+			}
+			value = inner.Bytes()
+		}
+		buf.Write(tag)
+		writeLength(buf, len(value))
+		buf.Write(value)
+	}
+	return nil
+}
+
+// writeLength writes l in the shortest BER form
+func writeLength(buf *bytes.Buffer, l int) {
+	switch {
+	case l < 0x80:
+		buf.WriteByte(byte(l))
+	case l <= 0xFF:
+		buf.Write([]byte{0x81, byte(l)})
+	case l <= 0xFFFF:
+		buf.Write([]byte{0x82, byte(l >> 8), byte(l)})
+	default:
+		buf.Write([]byte{0x83, byte(l >> 16), byte(l >> 8), byte(l)})
+	}
+}
+
+func joinPath(parent, tag string) string {
+	if parent == "" {
+		return tag
+	}
+	return parent + "." + tag
+}
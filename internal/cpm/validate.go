@@ -0,0 +1,118 @@
+package cpm
+
+import (
+	"fmt"
+	"regexp"
+
+	"thaiqr-go/internal/qr"
+)
+
+// lengths are the lengths in bytes allowed for the tags of an Application
+var lengths = map[string][2]int{
+	TagADFName:                   {5, 16},
+	TagLabel:                     {1, 16},
+	TagTrack2:                    {1, 19},
+	TagPAN:                       {1, 10},
+	TagCardholderName:            {2, 26},
+	TagLanguagePreference:        {2, 8},
+	TagApplicationVersion:        {2, 2},
+	TagIssuerApplicationData:     {1, 32},
+	TagApplicationCryptogram:     {8, 8},
+	TagCryptogramInformationData: {1, 1},
+	TagATC:                       {2, 2},
+	TagUnpredictableNumber:       {4, 4},
+}
+
+var (
+	hexFormat      = regexp.MustCompile(`^([0-9A-Fa-f]{2})*$`)
+	languageFormat = regexp.MustCompile(`^([a-z]{2}){1,4}$`)
+)
+
+// Validate checks every field of c and reports all problems found, using the
+// issue types of the qr package with tag paths such as "61.9F26"
+func Validate(c *CPM) *qr.Report {
+	r := &qr.Report{}
+	if c.PayloadFormatIndicator != "" && c.PayloadFormatIndicator != PayloadFormatIndicator {
+		add(r, TagPayloadFormatIndicator, qr.RuleFormat, "Payload Format Indicator must be %q but got %q", PayloadFormatIndicator, c.PayloadFormatIndicator)
+	}
+	if len(c.Applications) == 0 {
+		add(r, TagApplicationTemplate, qr.RuleMandatory, "at least one Application Template is mandatory")
+	}
+	var common Application
+	if c.CommonData != nil {
+		common = *c.CommonData
+		validateApplication(r, TagCommonDataTemplate, &common)
+	}
+	for i := range c.Applications {
+		a := &c.Applications[i]
+		validateApplication(r, TagApplicationTemplate, a)
+		if a.ADFName == "" {
+			add(r, TagApplicationTemplate+"."+TagADFName, qr.RuleMandatory, "ADF Name is mandatory in every Application Template")
+		}
+		if a.Track2 == "" && a.PAN == "" && common.Track2 == "" && common.PAN == "" {
+			add(r, TagApplicationTemplate+"."+TagPAN, qr.RuleMandatory, "Application PAN or Track 2 Equivalent Data is mandatory")
+		}
+	}
+	return r
+}
+
+func validateApplication(r *qr.Report, tag string, a *Application) {
+	for _, f := range fields {
+		v := *f.get(a)
+		if v == "" {
+			continue
+		}
+		path := tag + "." + f.tag
+		n := len(v)
+		switch f.kind {
+		case kindHex:
+			if !hexFormat.MatchString(v) {
+				add(r, path, qr.RuleFormat, "must be an even number of hex digits but got %q", v)
+				continue
+			}
+			n = len(v) / 2
+		case kindPAN:
+			if !qr.IsDigits(v) {
+				add(r, path, qr.RuleFormat, "must be digits but got %q", v)
+				continue
+			}
+			if !qr.Luhn(v) {
+				add(r, path, qr.RuleFormat, "PAN %s fails the Luhn check", v)
+			}
+			n = (len(v) + 1) / 2
+		}
+		if l := lengths[f.tag]; n < l[0] || n > l[1] {
+			if l[0] == l[1] {
+				add(r, path, qr.RuleLength, "length must be %d bytes but got %d", l[0], n)
+			} else {
+				add(r, path, qr.RuleLength, "length must be %d to %d bytes but got %d", l[0], l[1], n)
+			}
+		}
+	}
+	if a.LanguagePreference != "" && !languageFormat.MatchString(a.LanguagePreference) {
+		add(r, tag+"."+TagLanguagePreference, qr.RuleLanguage, "must be 1 to 4 lower case ISO 639 codes but got %q", a.LanguagePreference)
+	}
+	cryptogram := []struct{ tag, value string }{
+		{TagApplicationCryptogram, a.ApplicationCryptogram},
+		{TagCryptogramInformationData, a.CryptogramInformationData},
+		{TagATC, a.ATC},
+	}
+	present := 0
+	for _, t := range cryptogram {
+		if t.value != "" {
+			present++
+		}
+	}
+	if present == 0 || present == len(cryptogram) {
+		return
+	}
+	for _, t := range cryptogram {
+		if t.value == "" {
+			add(r, tag+"."+t.tag, qr.RuleMandatory, "is mandatory with the other cryptogram data 9F26, 9F27 and 9F36")
+		}
+	}
+}
+
+func add(r *qr.Report, tag string, rule string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, qr.Issue{Tag: tag, Severity: qr.SeverityError, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
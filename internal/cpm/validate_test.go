@@ -0,0 +1,80 @@
+package cpm
+
+import (
+	"testing"
+
+	"thaiqr-go/internal/qr"
+)
+
+func TestValidateSpecExample(t *testing.T) {
+	c, err := Decode(specExample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the PAN of the example is not a real one
+	issues := Validate(c).Errors()
+	if len(issues) != 1 || issues[0].Tag != "62.5A" || issues[0].Rule != qr.RuleFormat {
+		t.Errorf("got %v, want the Luhn check of 62.5A", issues)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Application {
+		return Application{ADFName: "A0000000031010", PAN: "4111111111111111"}
+	}
+	tests := []struct {
+		name string
+		cpm  func(c *CPM)
+		tag  string // "" when valid
+		rule string
+	}{
+		{"valid", func(c *CPM) {}, "", ""},
+		{"PAN in the common data", func(c *CPM) {
+			c.Applications[0].PAN = ""
+			c.CommonData = &Application{PAN: "4111111111111111"}
+		}, "", ""},
+		{"track 2 only", func(c *CPM) {
+			c.Applications[0].PAN = ""
+			c.Applications[0].Track2 = "4111111111111111D2512201"
+		}, "", ""},
+		{"full cryptogram", func(c *CPM) {
+			a := &c.Applications[0]
+			a.ApplicationCryptogram, a.CryptogramInformationData, a.ATC = "1122334455667788", "80", "0001"
+		}, "", ""},
+		{"wrong version", func(c *CPM) { c.PayloadFormatIndicator = "CPV02" }, "85", qr.RuleFormat},
+		{"no application", func(c *CPM) { c.Applications = nil }, "61", qr.RuleMandatory},
+		{"no ADF name", func(c *CPM) { c.Applications[0].ADFName = "" }, "61.4F", qr.RuleMandatory},
+		{"no PAN", func(c *CPM) { c.Applications[0].PAN = "" }, "61.5A", qr.RuleMandatory},
+		{"odd hex", func(c *CPM) { c.Applications[0].ADFName = "A000000003101" }, "61.4F", qr.RuleFormat},
+		{"short ADF name", func(c *CPM) { c.Applications[0].ADFName = "A0000000" }, "61.4F", qr.RuleLength},
+		{"PAN not digits", func(c *CPM) { c.Applications[0].PAN = "41111111111111AB" }, "61.5A", qr.RuleFormat},
+		{"PAN fails Luhn", func(c *CPM) { c.Applications[0].PAN = "4111111111111112" }, "61.5A", qr.RuleFormat},
+		{"PAN too long", func(c *CPM) { c.Applications[0].PAN = "411111111111111111113" }, "61.5A", qr.RuleLength},
+		{"ATC length", func(c *CPM) { c.Applications[0].ATC = "01" }, "61.9F36", qr.RuleLength},
+		{"language", func(c *CPM) { c.Applications[0].LanguagePreference = "TH" }, "61.5F2D", qr.RuleLanguage},
+		{"common data language", func(c *CPM) { c.CommonData = &Application{LanguagePreference: "t"} }, "62.5F2D", qr.RuleLength},
+		{"cryptogram without ATC", func(c *CPM) {
+			a := &c.Applications[0]
+			a.ApplicationCryptogram, a.CryptogramInformationData = "1122334455667788", "80"
+		}, "61.9F36", qr.RuleMandatory},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CPM{Applications: []Application{valid()}}
+			tt.cpm(c)
+			r := Validate(c)
+			if tt.tag == "" {
+				if !r.Valid() {
+					t.Errorf("got %v, want no issue", r.Issues)
+				}
+				return
+			}
+			for _, i := range r.Errors() {
+				if i.Tag == tt.tag && i.Rule == tt.rule {
+					return
+				}
+			}
+			t.Errorf("got %v, want a %s issue at %s", r.Issues, tt.rule, tt.tag)
+		})
+	}
+}
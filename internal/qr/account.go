@@ -0,0 +1,96 @@
+package qr
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Globally Unique Identifiers of the Thai QR Payment merchant account templates
+const (
+	GUIDPromptPay    = "A000000677010111" // 29; PromptPay credit transfer
+	GUIDBillPayment  = "A000000677010112" // 30; PromptPay bill payment
+	GUIDPromptPayAPI = "A000000677010113" // 31; payment through the acquirer API
+)
+
+func init() {
+	RegisterTemplateType(GUIDPromptPay, QRMerchantIDPromptPay{})
+	RegisterTemplateType(GUIDBillPayment, QRMerchantIDPromptPayBillPayment{})
+	RegisterTemplateType(GUIDPromptPayAPI, QRMerchantIDPromptPayAPI{})
+}
+
+// RegisterTemplateType registers the decoder and encoder of the templates with
+// the given GUID for the struct type of v, declared with emv tags relative to
+// the template as described by Marshal. Decoded values are of the type of v,
+// a sub-tag 00 field gets the GUID.
+func RegisterTemplateType(guid string, v interface{}) {
+	t := reflect.TypeOf(v)
+	RegisterTemplate(guid, func(tmpl Template) (interface{}, error) {
+		fields := map[string]string{"00": tmpl.GUID}
+		for k, f := range tmpl.Fields {
+			fields[k] = f
+		}
+		value := reflect.New(t)
+		if err := unmarshalMap(fields, value.Elem()); err != nil {
+			return nil, err
+		}
+		return value.Elem().Interface(), nil
+	})
+	RegisterTemplateEncoder(guid, func(value interface{}) (map[string]string, error) {
+		if reflect.TypeOf(value) != t {
+			return nil, fmt.Errorf("template %s expects a %s, got %T", guid, t, value)
+		}
+		fields, err := marshalMap(value)
+		if err != nil {
+			return nil, err
+		}
+		delete(fields, "00")
+		return fields, nil
+	})
+}
+
+// hasField reports whether a field of QRMerchantID other than Schemes and
+// Accounts holds the Merchant Account Information tag
+func hasField(tag string) bool {
+	specs, _ := specsOf(reflect.TypeOf(QRMerchantID{}))
+	for _, s := range specs {
+		if len(s.path) == 1 && s.path[0] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// templateAt splits the template of tag id in m, an empty template when the tag
+// is missing or is not a template
+func templateAt(m map[string]string, id string) Template {
+	if m[id] == "" {
+		return Template{}
+	}
+	t, err := splitTemplate(id, m[id])
+	if err != nil {
+		return Template{}
+	}
+	return t
+}
+
+// merchantAccounts reads the tags 26-51 of m in ascending order. Tags that are
+// not templates are left to the legacy string fields of QRMerchantID and to
+// QR.DataObjectForMerchantAccountInformationByMasterCard for 51.
+func merchantAccounts(m map[string]string) ([]Template, error) {
+	var accounts []Template
+	for i := 26; i <= 51; i++ {
+		id := fmt.Sprintf("%02d", i)
+		if m[id] == "" {
+			continue
+		}
+		if t, err := splitTemplate(id, m[id]); err != nil || t.GUID == "" {
+			continue
+		}
+		t, err := decodeTemplate(id, m[id])
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, t)
+	}
+	return accounts, nil
+}
@@ -0,0 +1,96 @@
+package qr
+
+import (
+	"testing"
+)
+
+type testAcquirerAccount struct {
+	GUID       string `emv:"00"`
+	MerchantID string `emv:"01,max=10"`
+	TerminalID string `emv:"02"`
+}
+
+func TestMerchantAccountAt51(t *testing.T) {
+	RegisterTemplateType("COM.EXAMPLE.ACQ", testAcquirerAccount{})
+	payload := withCRC("00020101021129370016A00000067701011101130066812345678" +
+		"51350015COM.EXAMPLE.ACQ0106M123450202T153037645802TH")
+	q, err := DecodeQRVisa(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(q.Merchant.ID.Accounts) != 1 || q.Merchant.ID.Accounts[0].ID != "51" {
+		t.Fatalf("accounts %+v, want the template at 51", q.Merchant.ID.Accounts)
+	}
+	want := testAcquirerAccount{GUID: "COM.EXAMPLE.ACQ", MerchantID: "M12345", TerminalID: "T1"}
+	if v, ok := q.Merchant.ID.Accounts[0].Value.(testAcquirerAccount); !ok || v != want {
+		t.Errorf("decoded %#v, want %#v", q.Merchant.ID.Accounts[0].Value, want)
+	}
+	if q.DataObjectForMerchantAccountInformationByMasterCard != "" {
+		t.Errorf("tag 51 also decoded into the legacy field: %q", q.DataObjectForMerchantAccountInformationByMasterCard)
+	}
+	if got := encodeForTest(t, q); got != payload {
+		t.Errorf("encoded %s, want %s", got, payload)
+	}
+
+	// Editing the decoded value is written back
+	v := q.Merchant.ID.Accounts[0].Value.(testAcquirerAccount)
+	v.TerminalID = "T2"
+	q.Merchant.ID.Accounts[0].Value = v
+	m, err := ConvertQRToMap(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["51"] != "0015COM.EXAMPLE.ACQ0106M123450202T2" {
+		t.Errorf("tag 51 %q", m["51"])
+	}
+}
+
+func TestLegacyTag51(t *testing.T) {
+	for _, value := range []string{"ABCDEFGHIJKLMNOPQRSTUVWXY", "not a template value"} {
+		payload := withCRC("00020101021129370016A00000067701011101130066812345678" +
+			"51" + twoDigits(len(value)) + value + "53037645802TH")
+		q, err := DecodeQRVisa(payload)
+		if err != nil {
+			t.Errorf("%q: %v", value, err)
+			continue
+		}
+		if q.DataObjectForMerchantAccountInformationByMasterCard != value || len(q.Merchant.ID.Accounts) != 0 {
+			t.Errorf("%q: decoded %q and accounts %v", value, q.DataObjectForMerchantAccountInformationByMasterCard, q.Merchant.ID.Accounts)
+		}
+		if got := encodeForTest(t, q); got != payload {
+			t.Errorf("encoded %s, want %s", got, payload)
+		}
+	}
+}
+
+func twoDigits(n int) string {
+	return string([]byte{byte('0' + n/10), byte('0' + n%10)})
+}
+
+// encodeForTest encodes q with a generated CRC
+func encodeForTest(t *testing.T, q *QR) string {
+	t.Helper()
+	m, err := ConvertQRToMap(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	delete(m, "63")
+	s, err := ConvertMapToString(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestQRISTag51(t *testing.T) {
+	qris := withCRC("000201010211" + "26310017ID.CO.EXAMPLE.WWW0106ABC123" +
+		"51440014ID.CO.QRIS.WWW0215ID10200000000010303UMI" +
+		"520454995303360" + "5802ID5904SHOP6007JAKARTA")
+	q, err := (&Decoder{}).DecodeString(qris)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(q.Merchant.ID.Accounts); n != 1 || q.Merchant.ID.Accounts[0].GUID != "ID.CO.QRIS.WWW" {
+		t.Errorf("accounts %+v, want the QRIS template at 51", q.Merchant.ID.Accounts)
+	}
+}
@@ -0,0 +1,58 @@
+package qr
+
+import (
+	"fmt"
+)
+
+// MerchantChannel is the decoded Merchant Channel (62.11)
+type MerchantChannel struct {
+	Media        string // 1st character
+	Location     string // 2nd character; transaction location
+	Presentation string // 3rd character; merchant presentation
+}
+
+var (
+	channelMedia = map[byte]string{
+		'0': "Print - Merchant sticker",
+		'1': "Print - Bill/Invoice",
+		'2': "Print - Magazine/Poster",
+		'3': "Print - Other",
+		'4': "Screen/Electronic - Merchant POS/POI",
+		'5': "Screen/Electronic - Website",
+		'6': "Screen/Electronic - App",
+		'7': "Screen/Electronic - Other",
+	}
+	channelLocation = map[byte]string{
+		'0': "At Merchant premises/registered address",
+		'1': "Not at Merchant premises/registered address",
+		'2': "Remote Commerce",
+		'3': "Other",
+	}
+	channelPresentation = map[byte]string{
+		'0': "Attended POI",
+		'1': "Unattended",
+		'2': "Semi-attended (self-checkout)",
+		'3': "Other",
+	}
+)
+
+// ParseMerchantChannel checks the 3 characters of a Merchant Channel against
+// their defined values and returns their descriptions.
+func ParseMerchantChannel(code string) (MerchantChannel, error) {
+	if len(code) != 3 {
+		return MerchantChannel{}, tagError("62.11", ErrBadLength, "3", fmt.Sprint(len(code)))
+	}
+	media, ok := channelMedia[code[0]]
+	if !ok {
+		return MerchantChannel{}, tagError("62.11", ErrBadValue, "media type 0-7", code[:1])
+	}
+	location, ok := channelLocation[code[1]]
+	if !ok {
+		return MerchantChannel{}, tagError("62.11", ErrBadValue, "transaction location 0-3", code[1:2])
+	}
+	presentation, ok := channelPresentation[code[2]]
+	if !ok {
+		return MerchantChannel{}, tagError("62.11", ErrBadValue, "merchant presentation 0-3", code[2:])
+	}
+	return MerchantChannel{Media: media, Location: location, Presentation: presentation}, nil
+}
@@ -0,0 +1,188 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Amount is an exact decimal amount kept in minor units, such as satang for baht.
+// The zero value is an absent amount. An amount decoded from a payload keeps its
+// text, such as "100.5", which MarshalEMV writes back unchanged so that the
+// payload keeps its CRC.
+type Amount struct {
+	minor    int64
+	exponent int
+	set      bool
+	text     string // as decoded, "" for a computed amount
+}
+
+// ErrAmountOverflow is returned by Amount arithmetic that does not fit in 13 characters
+var ErrAmountOverflow = errors.New("amount overflow")
+
+// maxAmountLength is the longest value of tags 54 and 56
+const maxAmountLength = 13
+
+// NewAmount returns the amount of minor units with the given exponent, e.g. NewAmount(10050, 2) is 100.50.
+// EMVCo amounts cannot be negative.
+func NewAmount(minor int64, exponent int) (Amount, error) {
+	if minor < 0 {
+		return Amount{}, fmt.Errorf("amount %d must not be negative", minor)
+	}
+	if exponent < 0 || exponent > 18 {
+		return Amount{}, fmt.Errorf("amount exponent must be 0 to 18 but got %d", exponent)
+	}
+	return Amount{minor: minor, exponent: exponent, set: true}, nil
+}
+
+// ParseAmount parses an EMVCo amount: at most 13 characters of digits with an
+// optional "." followed by 1 to exponent decimals, such as "100" or "99.5".
+func ParseAmount(s string, exponent int) (Amount, error) {
+	if s == "" || len(s) > maxAmountLength {
+		return Amount{}, fmt.Errorf("amount %q must be 1 to %d characters", s, maxAmountLength)
+	}
+	whole, frac, dot := s, "", false
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		whole, frac, dot = s[:i], s[i+1:], true
+	}
+	if !IsDigits(whole) || (dot && !IsDigits(frac)) {
+		return Amount{}, fmt.Errorf("amount %q must be digits with an optional \".\" and decimals", s)
+	}
+	if len(frac) > exponent {
+		return Amount{}, fmt.Errorf("amount %q has more than %d decimals", s, exponent)
+	}
+	var minor int64
+	for _, c := range whole + frac + strings.Repeat("0", exponent-len(frac)) {
+		minor = minor*10 + int64(c-'0')
+	}
+	return NewAmount(minor, exponent)
+}
+
+// IsSet reports whether the amount is present
+func (a Amount) IsSet() bool {
+	return a.set
+}
+
+// IsZero reports whether the amount is absent or 0
+func (a Amount) IsZero() bool {
+	return a.minor == 0
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return a.minor
+}
+
+// Exponent returns the number of decimals
+func (a Amount) Exponent() int {
+	return a.exponent
+}
+
+// String formats the amount with all its decimals, such as "100.00", and "" when absent
+func (a Amount) String() string {
+	if !a.set {
+		return ""
+	}
+	if a.exponent == 0 {
+		return fmt.Sprint(a.minor)
+	}
+	unit := int64(math.Pow10(a.exponent))
+	return fmt.Sprintf("%d.%0*d", a.minor/unit, a.exponent, a.minor%unit)
+}
+
+// Add returns a + b, both amounts must have the same exponent
+func (a Amount) Add(b Amount) (Amount, error) {
+	if !b.set {
+		return a, nil
+	}
+	if !a.set {
+		return b, nil
+	}
+	if a.exponent != b.exponent {
+		return Amount{}, fmt.Errorf("cannot add amounts with %d and %d decimals", a.exponent, b.exponent)
+	}
+	return checked(a.minor+b.minor, a.exponent)
+}
+
+// Sub returns a - b, both amounts must have the same exponent and the result must not be negative
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if !b.set {
+		return a, nil
+	}
+	if a.exponent != b.exponent {
+		return Amount{}, fmt.Errorf("cannot subtract amounts with %d and %d decimals", a.exponent, b.exponent)
+	}
+	if a.minor < b.minor {
+		return Amount{}, fmt.Errorf("amount %s is less than %s", a, b)
+	}
+	return checked(a.minor-b.minor, a.exponent)
+}
+
+// Percent returns pct percent of a rounded half up to the minor unit, pct
+// is read with 2 decimals such as "3.5" for 3.50%
+func (a Amount) Percent(pct string) (Amount, error) {
+	p, err := ParseAmount(pct, 2)
+	if err != nil {
+		return Amount{}, err
+	}
+	if a.minor > math.MaxInt64/(p.minor+1) {
+		return Amount{}, ErrAmountOverflow
+	}
+	return checked((a.minor*p.minor+5000)/10000, a.exponent)
+}
+
+// checked returns the amount when it still fits in 13 characters
+func checked(minor int64, exponent int) (Amount, error) {
+	a, err := NewAmount(minor, exponent)
+	if err != nil || len(a.String()) > maxAmountLength {
+		return Amount{}, ErrAmountOverflow
+	}
+	return a, nil
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler like UnmarshalEMV, an empty text is an absent amount
+func (a *Amount) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*a = Amount{}
+		return nil
+	}
+	return a.UnmarshalEMV(string(text))
+}
+
+// MarshalEMV implements Marshaler, writing a decoded amount as it was decoded
+func (a Amount) MarshalEMV() (string, error) {
+	if a.text != "" {
+		return a.text, nil
+	}
+	return a.String(), nil
+}
+
+// UnmarshalEMV implements Unmarshaler, the exponent is the number of decimals found in value
+func (a *Amount) UnmarshalEMV(value string) error {
+	exponent := 0
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		exponent = len(value) - i - 1
+	}
+	v, err := decodeAmount(value, exponent)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// decodeAmount parses an amount read from a payload and keeps its text
+func decodeAmount(s string, exponent int) (Amount, error) {
+	a, err := ParseAmount(s, exponent)
+	if err != nil {
+		return Amount{}, err
+	}
+	a.text = s
+	return a, nil
+}
@@ -0,0 +1,129 @@
+package qr
+
+import "testing"
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in       string
+		exponent int
+		minor    int64 // -1 when invalid
+	}{
+		{"100", 2, 10000},
+		{"99.5", 2, 9950},
+		{"0.01", 2, 1},
+		{"1234567890123", 0, 1234567890123},
+		{"7", 0, 7},
+		{"", 2, -1},
+		{"100.", 2, -1},
+		{"100.", 0, -1},
+		{".5", 2, -1},
+		{"1.234", 2, -1},
+		{"1.5", 0, -1},
+		{"1,00", 2, -1},
+		{"1.0.0", 2, -1},
+		{"-1", 2, -1},
+		{"12345678901234", 2, -1},
+	}
+	for _, tt := range tests {
+		a, err := ParseAmount(tt.in, tt.exponent)
+		if tt.minor < 0 {
+			if err == nil {
+				t.Errorf("ParseAmount(%q, %d) = %s, want an error", tt.in, tt.exponent, a)
+			}
+			continue
+		}
+		if err != nil || a.Minor() != tt.minor || a.Exponent() != tt.exponent || !a.IsSet() {
+			t.Errorf("ParseAmount(%q, %d) = %d, %v, want %d", tt.in, tt.exponent, a.Minor(), err, tt.minor)
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	amount := func(s string) Amount {
+		a, err := ParseAmount(s, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a
+	}
+	yen, _ := NewAmount(500, 0)
+	tests := []struct {
+		name string
+		op   func() (Amount, error)
+		want string // "" for an unset amount, "error" for an error
+	}{
+		{"add", func() (Amount, error) { return amount("1.5").Add(amount("2.25")) }, "3.75"},
+		{"add unset", func() (Amount, error) { return amount("1.5").Add(Amount{}) }, "1.50"},
+		{"add to unset", func() (Amount, error) { return Amount{}.Add(amount("1.5")) }, "1.50"},
+		{"add both unset", func() (Amount, error) { return Amount{}.Add(Amount{}) }, ""},
+		{"add exponents", func() (Amount, error) { return amount("1").Add(yen) }, "error"},
+		{"add overflow", func() (Amount, error) { return amount("9999999999.99").Add(amount("0.01")) }, "error"},
+		{"sub", func() (Amount, error) { return amount("5").Sub(amount("2.5")) }, "2.50"},
+		{"sub unset", func() (Amount, error) { return amount("5").Sub(Amount{}) }, "5.00"},
+		{"sub both unset", func() (Amount, error) { return Amount{}.Sub(Amount{}) }, ""},
+		{"sub below 0", func() (Amount, error) { return amount("1").Sub(amount("2")) }, "error"},
+		{"sub exponents", func() (Amount, error) { return amount("1").Sub(yen) }, "error"},
+		{"percent", func() (Amount, error) { return amount("200").Percent("3.5") }, "7.00"},
+		{"percent rounds half up", func() (Amount, error) { return amount("0.10").Percent("5") }, "0.01"},
+	}
+	for _, tt := range tests {
+		got, err := tt.op()
+		switch {
+		case tt.want == "error":
+			if err == nil {
+				t.Errorf("%s: got %s, want an error", tt.name, got)
+			}
+		case err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case got.String() != tt.want || got.IsSet() != (tt.want != ""):
+			t.Errorf("%s: got %q set %v, want %q", tt.name, got, got.IsSet(), tt.want)
+		}
+	}
+}
+
+func TestAmountKeepsDecodedText(t *testing.T) {
+	payload := withCRC("00020101021229370016A000000677010111011300668123456785204581453037645405100.55802TH5904SHOP6007BANGKOK62070503ABC")
+	q, err := DecodeQRVisa(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a := q.Transaction.Amount; a.String() != "100.50" || a.Minor() != 10050 {
+		t.Errorf("Amount = %s (%d minor units), want 100.50", a, a.Minor())
+	}
+	m, err := ConvertQRToMap(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m["54"] != "100.5" {
+		t.Errorf("54 = %q, want %q", m["54"], "100.5")
+	}
+	s, err := ConvertMapToString(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != payload {
+		t.Errorf("re-encoded\n%s\nwant\n%s", s, payload)
+	}
+
+	// a computed amount is written with all its decimals
+	q.Transaction.Amount, _ = q.Transaction.Amount.Add(Amount{})
+	if v, _ := q.Transaction.Amount.MarshalEMV(); v != "100.5" {
+		t.Errorf("adding nothing: MarshalEMV = %q, want %q", v, "100.5")
+	}
+	tip, _ := NewAmount(50, 2)
+	q.Transaction.Amount, _ = q.Transaction.Amount.Add(tip)
+	if v, _ := q.Transaction.Amount.MarshalEMV(); v != "101.00" {
+		t.Errorf("MarshalEMV = %q, want %q", v, "101.00")
+	}
+
+	var a Amount
+	if err := a.UnmarshalEMV("100."); err == nil {
+		t.Error(`UnmarshalEMV("100.") succeeded`)
+	}
+	if err := a.UnmarshalText([]byte("12.5")); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := a.MarshalEMV(); v != "12.5" || a.Exponent() != 1 {
+		t.Errorf("UnmarshalText(12.5): MarshalEMV = %q exponent %d", v, a.Exponent())
+	}
+}
@@ -0,0 +1,18 @@
+package qr
+
+import (
+	"regexp"
+)
+
+var billReferenceFormat = regexp.MustCompile(`^[A-Z0-9]{1,20}$`)
+
+// ValidBillerID reports whether id is a 15 digits Biller ID: a 13 digits tax ID
+// with a valid check digit followed by a 2 digits suffix
+func ValidBillerID(id string) bool {
+	return len(id) == 15 && IsDigits(id) && ValidThaiID(id[:13])
+}
+
+// ValidBillReference reports whether ref is 1 to 20 upper case letters or digits
+func ValidBillReference(ref string) bool {
+	return billReferenceFormat.MatchString(ref)
+}
@@ -0,0 +1,97 @@
+package qr
+
+import (
+	"bufio"
+)
+
+// Decoder decodes QR strings into QR structs. Countries and Currencies restrict
+// the accepted ISO 3166 countries and ISO 4217 currencies, empty accepts any.
+// The rules of the Profile are checked too. When Profile is nil the profile
+// detected from the payload is used, and a Countries or Currencies policy that
+// is set takes precedence over the country or currencies of a built-in one:
+// the zero value rejects a TH payload in 840, Currencies {"764", "840"} accepts it.
+// A Decoder keeps no state between DecodeString calls, so a single Decoder can
+// be shared by many goroutines once configured. A Decoder made by NewDecoder
+// also reads payloads from its reader and must only be used by one goroutine.
+type Decoder struct {
+	Countries  []string // accepted Country Codes (58), such as "TH"
+	Currencies []string // accepted Transaction Currencies (53), such as "764" or "THB"
+	Profile    Profile  // rules to check, nil detects the profile from the payload
+
+	r    *bufio.Reader // set by NewDecoder
+	next string        // payload read ahead by More
+	err  error         // error that ended the stream
+}
+
+// defaultDecoder only accepts Thai QR, it is used by DecodeQRVisa and ConvertMapToQR
+var defaultDecoder = Decoder{Countries: []string{"TH"}, Currencies: []string{"764"}, Profile: ThaiQR}
+
+// DecodeString parses s, checks its CRC against the original string and
+// converts it to a QR struct. Errors are returned as *ParseError.
+func (d *Decoder) DecodeString(s string) (*QR, error) {
+	p, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	crc := p.Get("63")
+	if crc == nil {
+		return nil, &ParseError{Offset: len(s), Path: "63", Err: ErrMissingTag}
+	}
+	if last := p.Nodes[len(p.Nodes)-1]; crc != last {
+		return nil, &ParseError{Offset: crc.Offset, Path: "63", Expected: "last tag", Actual: "followed by tag " + last.ID, Err: ErrBadTag}
+	}
+	if err := checkCRC(s[:crc.Offset+4], crc.Value); err != nil {
+		return nil, withOffset(p, err)
+	}
+	if err := d.checkProfile(p); err != nil {
+		return nil, withOffset(p, err)
+	}
+	m := make(map[string]string, len(p.Nodes))
+	for _, n := range p.Nodes {
+		m[n.ID] = n.Value
+	}
+	qr, err := mapToQR(m, d)
+	if err != nil {
+		return nil, withOffset(p, err)
+	}
+	return qr, nil
+}
+
+// checkProfile checks p against the rules of d.Profile, or of the detected
+// profile whose country and currencies yield to the policy of d
+func (d *Decoder) checkProfile(p *Payload) error {
+	if d.Profile != nil {
+		return d.Profile.Check(p)
+	}
+	switch pr := DetectProfile(p).(type) {
+	case nil:
+		return nil
+	case *profile:
+		return pr.check(p, len(d.Countries) == 0, len(d.Currencies) == 0)
+	default:
+		return pr.Check(p)
+	}
+}
+
+// withOffset fills in the offset of a ParseError from the tag path
+func withOffset(p *Payload, err error) error {
+	pe, ok := err.(*ParseError)
+	if !ok || pe.Offset >= 0 {
+		return err
+	}
+	if n := p.Find(pe.Path); n != nil {
+		pe.Offset = n.Offset
+		return err
+	}
+	// The tag is inside a malformed template, parse it again to find where it breaks
+	if len(pe.Path) < 2 {
+		return err
+	}
+	if n := p.Get(pe.Path[:2]); n != nil {
+		if _, perr := parseNodes(n.Value, n.Offset+4, n.ID); perr != nil {
+			return perr
+		}
+		pe.Offset = n.Offset
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/howeyc/crc16"
+)
+
+// withCRC appends the CRC (63) of s
+func withCRC(s string) string {
+	s += "6304"
+	return s + fmt.Sprintf("%04X", crc16.ChecksumCCITTFalse([]byte(s)))
+}
+
+var decoderPayloads = []struct {
+	payload string
+	amount  string
+	crc     string
+}{
+	{"000201010211021649570300000080620415520473000001046153134300764005204460000000000111565204530953037645802TH5918KBANK Merchant UAT6007bangkok62210505213460708709999955125000412340106416971020312363048169", "", "8169"},
+	{"00020101021229370016A000000677010111011300668123456785204581453037645406100.505802TH5904SHOP6007BANGKOK62070503ABC6304FF19", "100.50", "FF19"},
+	{"00020101021129370016A0000006770101110213123456789012153037645802TH6304C3BF", "", "C3BF"},
+	{"00020101021230530016A00000067701011201150994000165501000204REF10302R2530376454045.005802TH6304BBA4", "5.00", "BBA4"},
+}
+
+// TestDecoderParallel shares one Decoder across goroutines, run it with -race
+func TestDecoderParallel(t *testing.T) {
+	const (
+		goroutines = 32
+		rounds     = 100
+	)
+	d := &Decoder{Countries: []string{"TH"}, Currencies: []string{"764"}}
+	var wg sync.WaitGroup
+	errs := make(chan string, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				want := decoderPayloads[(g+i)%len(decoderPayloads)]
+				decode := d.DecodeString
+				if i%2 == 1 {
+					decode = DecodeQRVisa
+				}
+				q, err := decode(want.payload)
+				if err != nil {
+					errs <- err.Error()
+					return
+				}
+				if q.CRC != want.crc || q.Transaction.Amount.String() != want.amount {
+					errs <- "decoded " + q.CRC + " " + q.Transaction.Amount.String() + ", want " + want.crc + " " + want.amount
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+// TestDecoderParallelErrors checks that failing payloads do not affect the others
+func TestDecoderParallelErrors(t *testing.T) {
+	bad := decoderPayloads[1].payload[:len(decoderPayloads[1].payload)-4] + "0000"
+	var wg sync.WaitGroup
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if g%2 == 0 {
+					if _, err := DecodeQRVisa(bad); err == nil {
+						t.Error("CRC mismatch not reported")
+						return
+					}
+				} else if _, err := DecodeQRVisa(decoderPayloads[2].payload); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestDecoderPolicyOverridesDetectedProfile(t *testing.T) {
+	usd := withCRC("00020101021129370016A00000067701011101130066812345678530384054031005802TH")
+	tests := []struct {
+		name    string
+		decoder Decoder
+		err     error
+	}{
+		{"zero value uses the Thai profile", Decoder{}, ErrUnsupportedCurrency},
+		{"currency policy", Decoder{Currencies: []string{"764", "840"}}, nil},
+		{"currency policy without 840", Decoder{Currencies: []string{"764"}}, ErrUnsupportedCurrency},
+		{"explicit profile", Decoder{Currencies: []string{"764", "840"}, Profile: ThaiQR}, ErrUnsupportedCurrency},
+	}
+	for _, tt := range tests {
+		q, err := tt.decoder.DecodeString(usd)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && (q.Transaction.CurrencyCode != "840" || q.CountryCode != "TH") {
+			t.Errorf("%s: decoded %s/%s", tt.name, q.CountryCode, q.Transaction.CurrencyCode)
+		}
+	}
+}
+
+func TestDecoderCountryPolicy(t *testing.T) {
+	sg := withCRC("00020101021129370016A0000006770101110113006681234567853037645802SG")
+	if _, err := (&Decoder{Countries: []string{"TH", "SG"}}).DecodeString(sg); err != nil {
+		t.Errorf("SG payload with a TH, SG policy: %v", err)
+	}
+}
@@ -0,0 +1,56 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Sentinel errors wrapped by ParseError, use errors.Is to check them
+var (
+	ErrCRCMismatch         = errors.New("CRC mismatch")
+	ErrTruncated           = errors.New("data is truncated")
+	ErrBadLength           = errors.New("bad length")
+	ErrBadTag              = errors.New("bad tag ID")
+	ErrBadValue            = errors.New("bad value")
+	ErrMissingTag          = errors.New("missing mandatory tag")
+	ErrUnsupportedCountry  = errors.New("unsupported country")
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+)
+
+// ParseError describes where and why a QR string could not be decoded
+type ParseError struct {
+	Offset   int    // byte offset in the QR string; -1 when unknown
+	Path     string // tag path such as "62.05"
+	Expected string
+	Actual   string
+	Err      error // one of the sentinel errors
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Err.Error()
+	if e.Path != "" {
+		msg = fmt.Sprintf("tag %s: %s", e.Path, msg)
+	}
+	if e.Offset >= 0 {
+		msg = fmt.Sprintf("offset %d: %s", e.Offset, msg)
+	}
+	if e.Expected != "" || e.Actual != "" {
+		msg = fmt.Sprintf("%s (expected %q, got %q)", msg, e.Expected, e.Actual)
+	}
+	return "qr: " + msg
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// tagError returns a ParseError with an unknown offset for the tag at path
+func tagError(path string, err error, expected, actual string) *ParseError {
+	return &ParseError{Offset: -1, Path: path, Expected: expected, Actual: actual, Err: err}
+}
+
+// lengthError returns an ErrBadLength ParseError for a value of the wrong length
+func lengthError(path string, expected int, value string) *ParseError {
+	return tagError(path, ErrBadLength, fmt.Sprint(expected), fmt.Sprint(utf8.RuneCountInString(value)))
+}
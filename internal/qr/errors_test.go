@@ -0,0 +1,57 @@
+package qr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseErrorOffsets(t *testing.T) {
+	// base ends at offset 60
+	const base = "000201010211" + "29370016A00000067701011101130066812345678" + "5303764"
+	tests := []struct {
+		name    string
+		payload string
+		offset  int
+		path    string
+		err     error
+	}{
+		{"truncated tag", "0002010", 6, "", ErrTruncated},
+		{"truncated value", "0002015802T", 6, "58", ErrTruncated},
+		{"bad tag ID", "000201A102TH", 6, "", ErrBadTag},
+		{"bad length", "00020158X2TH", 8, "58", ErrBadLength},
+		{"bad tag ID after a short template", withCRC(base + "5802TH" + "62070503ABCD"), 77, "", ErrBadTag},
+		{"missing CRC", base + "5802TH", 66, "63", ErrMissingTag},
+		{"CRC not last", decoderPayloads[1].payload + "5802TH", 114, "63", ErrBadTag},
+		{"CRC mismatch", decoderPayloads[1].payload[:118] + "FF18", 114, "63", ErrCRCMismatch},
+		{"truncated sub-tag", withCRC(base + "5802TH" + "62050599X"), 70, "62.05", ErrTruncated},
+		{"point of initiation", withCRC("000201010213" + base[12:] + "5802TH"), 6, "01", ErrBadValue},
+		{"amount", withCRC(base + "54051.2.3" + "5802TH"), 60, "54", ErrBadValue},
+		{"country", withCRC(base + "5802US"), 60, "58", ErrUnsupportedCountry},
+	}
+	for _, tt := range tests {
+		_, err := defaultDecoder.DecodeString(tt.payload)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Offset != tt.offset || pe.Path != tt.path || !errors.Is(err, tt.err) {
+			t.Errorf("%s: DecodeString(%s) error = %v, want %v at %s offset %d", tt.name, tt.payload, err, tt.err, tt.path, tt.offset)
+		}
+	}
+}
+
+func TestParseErrorString(t *testing.T) {
+	tests := []struct {
+		err  *ParseError
+		want string
+	}{
+		{&ParseError{Offset: 6, Err: ErrTruncated}, "qr: offset 6: data is truncated"},
+		{&ParseError{Offset: 114, Path: "63", Expected: "FF19", Actual: "FF18", Err: ErrCRCMismatch},
+			`qr: offset 114: tag 63: CRC mismatch (expected "FF19", got "FF18")`},
+		{tagError("58", ErrUnsupportedCountry, "TH", "US"), `qr: tag 58: unsupported country (expected "TH", got "US")`},
+		{lengthError("00", 2, "1"), `qr: tag 00: bad length (expected "2", got "1")`},
+		{lengthError("59", 25, "ร้านกาแฟ"), `qr: tag 59: bad length (expected "25", got "8")`},
+	}
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.want {
+			t.Errorf("Error() = %s, want %s", got, tt.want)
+		}
+	}
+}
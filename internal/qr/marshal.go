@@ -0,0 +1,584 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Marshaler is implemented by types that encode themselves into the value of a data object
+type Marshaler interface {
+	MarshalEMV() (string, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from the value of a data object
+type Unmarshaler interface {
+	UnmarshalEMV(value string) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the EMVCo encoding of v, a struct or a pointer to a struct,
+// with the data objects in ascending tag order. No CRC is added.
+//
+// Each field is declared by its "emv" struct tag: the tag path, such as "59"
+// or "29.01" for sub-tag 01 of template 29, followed by options:
+//
+//	len=N     the value must be exactly N characters
+//	min=N     the value must be at least N characters
+//	max=N     the value must be at most N characters
+//	format=F  N for digits, A for letters, AN for letters and digits, ANS for printable ASCII
+//	inline    the fields of the struct are at the level of the enclosing struct
+//
+// A string field, or a type implementing Marshaler, is a primitive value. A
+// struct field is a template holding its own tagged fields. A map[string]string
+// field with a range path such as "62.50-99" holds the sub-tags of that range.
+// Struct fields without tag are inline, other fields without tag, fields
+// tagged "-" and empty values are left out.
+func Marshal(v interface{}) (string, error) {
+	m, err := marshalMap(v)
+	if err != nil {
+		return "", err
+	}
+	var str bytes.Buffer
+	for _, k := range sortedKeys(m) {
+		writeSubTag(&str, k, m[k])
+	}
+	return str.String(), nil
+}
+
+// Unmarshal decodes the EMVCo data objects of data into v, a pointer to a struct
+// declared with emv tags as described by Marshal. The CRC is not checked.
+// Values breaking the options of their tag are still stored in v and the first
+// such problem is returned as a *ParseError.
+func Unmarshal(data string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("qr: Unmarshal needs a non-nil pointer to a struct, got %T", v)
+	}
+	m, err := parseMap(data, "")
+	if err != nil {
+		return err
+	}
+	return unmarshalMap(m, rv.Elem())
+}
+
+// fieldSpec is a struct field declared with an emv tag
+type fieldSpec struct {
+	index            int
+	name             string
+	path             []string // tag IDs from the enclosing struct
+	from, to         int      // sub-tag range of a map field
+	inline           bool
+	length, min, max int
+	format           string
+}
+
+var specCache sync.Map // reflect.Type to []fieldSpec
+
+// specsOf returns the declared fields of the struct type t
+func specsOf(t reflect.Type) ([]fieldSpec, error) {
+	if specs, ok := specCache.Load(t); ok {
+		return specs.([]fieldSpec), nil
+	}
+	var specs []fieldSpec
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, tagged := f.Tag.Lookup("emv")
+		if f.PkgPath != "" || tag == "-" {
+			continue
+		}
+		if !tagged {
+			if isStruct(f.Type) {
+				specs = append(specs, fieldSpec{index: i, name: f.Name, inline: true})
+			}
+			continue
+		}
+		s, err := parseSpec(tag)
+		if err != nil {
+			return nil, fmt.Errorf("qr: field %s.%s: %v", t.Name(), f.Name, err)
+		}
+		s.index, s.name = i, f.Name
+		specs = append(specs, s)
+	}
+	specCache.Store(t, specs)
+	return specs, nil
+}
+
+// isStruct reports whether t is a struct, or pointer to a struct, encoded as a template
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	p := reflect.PtrTo(t)
+	return t.Kind() == reflect.Struct && !p.Implements(marshalerType) && !p.Implements(unmarshalerType)
+}
+
+// parseSpec reads an emv tag such as "29.01,max=13,format=N"
+func parseSpec(tag string) (fieldSpec, error) {
+	var s fieldSpec
+	parts := strings.Split(tag, ",")
+	for _, opt := range parts[1:] {
+		key, value := opt, ""
+		if i := strings.IndexByte(opt, '='); i >= 0 {
+			key, value = opt[:i], opt[i+1:]
+		}
+		var err error
+		switch key {
+		case "inline":
+			s.inline = true
+		case "len":
+			s.length, err = strconv.Atoi(value)
+		case "min":
+			s.min, err = strconv.Atoi(value)
+		case "max":
+			s.max, err = strconv.Atoi(value)
+		case "format":
+			if formats[value] == nil {
+				return s, fmt.Errorf("unknown format %q", value)
+			}
+			s.format = value
+		default:
+			return s, fmt.Errorf("unknown option %q", opt)
+		}
+		if err != nil {
+			return s, fmt.Errorf("bad option %q", opt)
+		}
+	}
+	if parts[0] == "" {
+		if !s.inline {
+			return s, fmt.Errorf("missing tag ID in %q", tag)
+		}
+		return s, nil
+	}
+	s.path = strings.Split(parts[0], ".")
+	for i, id := range s.path {
+		if i == len(s.path)-1 && strings.Contains(id, "-") {
+			r := strings.SplitN(id, "-", 2)
+			if len(r[0]) != 2 || len(r[1]) != 2 || !IsDigits(r[0]) || !IsDigits(r[1]) {
+				return s, fmt.Errorf("bad sub-tag range %q", id)
+			}
+			s.from, _ = strconv.Atoi(r[0])
+			s.to, _ = strconv.Atoi(r[1])
+			s.path = s.path[:i]
+			continue
+		}
+		if len(id) != 2 || !IsDigits(id) {
+			return s, fmt.Errorf("bad tag ID %q", id)
+		}
+	}
+	return s, nil
+}
+
+// isRange reports whether the field is a map of the sub-tags between from and to
+func (s fieldSpec) isRange() bool {
+	return s.to > 0
+}
+
+var formats = map[string]func(string) bool{
+	"N": IsDigits,
+	"A": func(s string) bool {
+		for _, c := range s {
+			if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+				return false
+			}
+		}
+		return true
+	},
+	"AN": func(s string) bool {
+		for _, c := range s {
+			if !('0' <= c && c <= '9' || 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z') {
+				return false
+			}
+		}
+		return true
+	},
+	"ANS": func(s string) bool {
+		for _, c := range s {
+			if c < 0x20 || c > 0x7E {
+				return false
+			}
+		}
+		return true
+	},
+	"S": func(string) bool { return true },
+}
+
+// check returns a ParseError when value breaks the options of s
+func (s fieldSpec) check(path, value string) error {
+	n := utf8.RuneCountInString(value)
+	switch {
+	case s.length > 0 && n != s.length:
+		return lengthError(path, s.length, value)
+	case s.min > 0 && n < s.min:
+		return tagError(path, ErrBadLength, "at least "+strconv.Itoa(s.min), strconv.Itoa(n))
+	case s.max > 0 && n > s.max:
+		return tagError(path, ErrBadLength, "at most "+strconv.Itoa(s.max), strconv.Itoa(n))
+	case s.format != "" && !formats[s.format](value):
+		return tagError(path, ErrBadValue, "format "+s.format, value)
+	}
+	return nil
+}
+
+// joinPath appends the tag IDs of path to the tag path prefix
+func joinPath(prefix string, path ...string) string {
+	p := strings.Join(path, ".")
+	if prefix == "" {
+		return p
+	}
+	if p == "" {
+		return prefix
+	}
+	return prefix + "." + p
+}
+
+// tlvTree is the data objects of one template level during marshalling
+type tlvTree map[string]*tlvNode
+
+type tlvNode struct {
+	value    string
+	children tlvTree
+}
+
+// node returns the node at path, creating it and its parents
+func (t tlvTree) node(path []string) *tlvNode {
+	n := t[path[0]]
+	if n == nil {
+		n = &tlvNode{}
+		t[path[0]] = n
+	}
+	if len(path) == 1 {
+		return n
+	}
+	if n.children == nil {
+		n.children = tlvTree{}
+	}
+	return n.children.node(path[1:])
+}
+
+// set stores the primitive value at path, a tag may only be set once
+func (t tlvTree) set(prefix string, path []string, value string) error {
+	n := t.node(path)
+	if n.value != "" || len(n.children) > 0 {
+		return fmt.Errorf("qr: tag %s is set twice", joinPath(prefix, path...))
+	}
+	n.value = value
+	return nil
+}
+
+// encode serializes the values of each tag of t, templates included
+func (t tlvTree) encode(prefix string) (map[string]string, error) {
+	ids := make([]string, 0, len(t))
+	for id := range t {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	m := make(map[string]string, len(t))
+	for _, id := range ids {
+		n := t[id]
+		v := n.value
+		if v == "" && n.children != nil {
+			sub, err := n.children.encode(joinPath(prefix, id))
+			if err != nil {
+				return nil, err
+			}
+			var str bytes.Buffer
+			for _, k := range sortedKeys(sub) {
+				writeSubTag(&str, k, sub[k])
+			}
+			v = str.String()
+		}
+		if v == "" {
+			continue
+		}
+		if l := utf8.RuneCountInString(v); l > 99 {
+			return nil, tagError(joinPath(prefix, id), ErrBadLength, "at most 99", strconv.Itoa(l))
+		}
+		m[id] = v
+	}
+	return m, nil
+}
+
+// marshalMap encodes the struct v into its top level tags
+func marshalMap(v interface{}) (map[string]string, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return map[string]string{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("qr: Marshal needs a struct, got %T", v)
+	}
+	t := tlvTree{}
+	if err := marshalStruct(t, "", rv); err != nil {
+		return nil, err
+	}
+	return t.encode("")
+}
+
+func marshalStruct(t tlvTree, prefix string, v reflect.Value) error {
+	specs, err := specsOf(v.Type())
+	if err != nil {
+		return err
+	}
+	for _, s := range specs {
+		fv := v.Field(s.index)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			fv = fv.Elem()
+		}
+		level, levelPrefix := t, prefix
+		if len(s.path) > 0 && (s.isRange() || isStruct(fv.Type())) {
+			n := t.node(s.path)
+			if n.value != "" {
+				return fmt.Errorf("qr: tag %s is set twice", joinPath(prefix, s.path...))
+			}
+			if n.children == nil {
+				n.children = tlvTree{}
+			}
+			level, levelPrefix = n.children, joinPath(prefix, s.path...)
+		}
+		switch {
+		case s.isRange():
+			if err := marshalRange(level, levelPrefix, s, fv); err != nil {
+				return err
+			}
+		case s.inline || isStruct(fv.Type()):
+			if fv.Kind() != reflect.Struct {
+				return fmt.Errorf("qr: field %s: inline needs a struct, got %s", s.name, fv.Type())
+			}
+			if err := marshalStruct(level, levelPrefix, fv); err != nil {
+				return err
+			}
+		default:
+			value, err := marshalValue(fv)
+			if err != nil {
+				return fmt.Errorf("qr: field %s: %v", s.name, err)
+			}
+			if value == "" {
+				continue
+			}
+			path := joinPath(prefix, s.path...)
+			if err := s.check(path, value); err != nil {
+				return err
+			}
+			if err := t.set(prefix, s.path, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// marshalValue returns the primitive value of v
+func marshalValue(v reflect.Value) (string, error) {
+	if m, ok := v.Interface().(Marshaler); ok {
+		return m.MarshalEMV()
+	}
+	if v.CanAddr() {
+		if m, ok := v.Addr().Interface().(Marshaler); ok {
+			return m.MarshalEMV()
+		}
+	}
+	if v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+// marshalRange writes the sub-tags of a map[string]string field
+func marshalRange(t tlvTree, prefix string, s fieldSpec, v reflect.Value) error {
+	m, ok := v.Interface().(map[string]string)
+	if !ok {
+		return fmt.Errorf("qr: field %s: a sub-tag range needs a map[string]string, got %s", s.name, v.Type())
+	}
+	for _, id := range sortedKeys(m) {
+		value := m[id]
+		if n, err := strconv.Atoi(id); err != nil || len(id) != 2 || n < s.from || n > s.to {
+			return tagError(joinPath(prefix, id), ErrBadTag, fmt.Sprintf("sub-tag between %02d and %02d", s.from, s.to), id)
+		}
+		if value == "" {
+			continue
+		}
+		if err := s.check(joinPath(prefix, id), value); err != nil {
+			return err
+		}
+		if err := t.set(prefix, []string{id}, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the tag IDs of m in ascending order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseMap parses the value of a template at path into a map of its tags
+func parseMap(value, path string) (map[string]string, error) {
+	nodes, err := parseNodes(value, 0, path)
+	if err != nil {
+		if pe, ok := err.(*ParseError); ok && path != "" {
+			pe.Offset = -1 // offset is relative to the template value
+		}
+		return nil, err
+	}
+	m := make(map[string]string, len(nodes))
+	for _, n := range nodes {
+		m[n.ID] = n.Value
+	}
+	return m, nil
+}
+
+// unmarshalMap decodes the tags of m into the struct v, it goes on after
+// a problem so that v gets every value and returns the first problem
+func unmarshalMap(m map[string]string, v reflect.Value) error {
+	d := &decodeState{}
+	d.decodeStruct(m, "", v)
+	return d.err
+}
+
+type decodeState struct {
+	err error
+}
+
+func (d *decodeState) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// lookup returns the value at path below the tags m
+func (d *decodeState) lookup(m map[string]string, prefix string, path []string) string {
+	value := m[path[0]]
+	for i := 1; i < len(path) && value != ""; i++ {
+		sub, err := parseMap(value, joinPath(prefix, path[:i]...))
+		if err != nil {
+			d.fail(err)
+			return ""
+		}
+		value = sub[path[i]]
+	}
+	return value
+}
+
+func (d *decodeState) decodeStruct(m map[string]string, prefix string, v reflect.Value) {
+	specs, err := specsOf(v.Type())
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	for _, s := range specs {
+		fv := v.Field(s.index)
+		if s.inline && len(s.path) == 0 {
+			d.decodeStruct(m, prefix, deref(fv))
+			continue
+		}
+		if s.isRange() {
+			level := m
+			if len(s.path) > 0 {
+				value := d.lookup(m, prefix, s.path)
+				if value == "" {
+					continue
+				}
+				var err error
+				if level, err = parseMap(value, joinPath(prefix, s.path...)); err != nil {
+					d.fail(err)
+					continue
+				}
+			}
+			d.decodeRange(level, joinPath(prefix, s.path...), s, fv)
+			continue
+		}
+		value := d.lookup(m, prefix, s.path)
+		if value == "" {
+			continue
+		}
+		path := joinPath(prefix, s.path...)
+		if isStruct(fv.Type()) {
+			sub, err := parseMap(value, path)
+			if err != nil {
+				d.fail(err)
+				continue
+			}
+			d.decodeStruct(sub, path, deref(fv))
+			continue
+		}
+		d.decodeValue(path, s, deref(fv), value)
+	}
+}
+
+// deref allocates the nil pointer v and returns what it points to
+func deref(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr {
+		return v
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return v.Elem()
+}
+
+func (d *decodeState) decodeValue(path string, s fieldSpec, v reflect.Value, value string) {
+	if u, ok := v.Addr().Interface().(Unmarshaler); ok {
+		if err := u.UnmarshalEMV(value); err != nil {
+			if _, ok := err.(*ParseError); !ok {
+				err = &ParseError{Offset: -1, Path: path, Expected: err.Error(), Actual: value, Err: ErrBadValue}
+			}
+			d.fail(err)
+		}
+		return
+	}
+	if v.Kind() != reflect.String {
+		d.fail(fmt.Errorf("qr: field %s: unsupported type %s", s.name, v.Type()))
+		return
+	}
+	v.SetString(value)
+	if err := s.check(path, value); err != nil {
+		d.fail(err)
+	}
+}
+
+// decodeRange fills a map[string]string field with the sub-tags of its range
+func (d *decodeState) decodeRange(m map[string]string, prefix string, s fieldSpec, v reflect.Value) {
+	if v.Type() != reflect.TypeOf(map[string]string(nil)) {
+		d.fail(fmt.Errorf("qr: field %s: a sub-tag range needs a map[string]string, got %s", s.name, v.Type()))
+		return
+	}
+	var out map[string]string
+	for _, id := range sortedKeys(m) {
+		value := m[id]
+		n, err := strconv.Atoi(id)
+		if err != nil || n < s.from || n > s.to || value == "" {
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[id] = value
+		if err := s.check(joinPath(prefix, id), value); err != nil {
+			d.fail(err)
+		}
+	}
+	if out != nil {
+		v.Set(reflect.ValueOf(out))
+	}
+}
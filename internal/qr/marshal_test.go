@@ -0,0 +1,209 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testCode is a numeric code encoding itself as 3 digits
+type testCode int
+
+func (c testCode) MarshalEMV() (string, error) {
+	if c < 0 || c > 999 {
+		return "", fmt.Errorf("code %d out of range", int(c))
+	}
+	return fmt.Sprintf("%03d", int(c)), nil
+}
+
+func (c *testCode) UnmarshalEMV(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || len(value) != 3 {
+		return errors.New("3 digits")
+	}
+	*c = testCode(n)
+	return nil
+}
+
+type testAccount struct {
+	GUID string `emv:"00,max=32,format=ANS"`
+	ID   string `emv:"01,len=13,format=N"`
+}
+
+type testName struct {
+	Name string `emv:"59,max=25"`
+	City string `emv:"60,max=15"`
+}
+
+type testPayload struct {
+	Version  string            `emv:"00,len=2,format=N"`
+	Account  testAccount       `emv:"29"`
+	Bill     string            `emv:"62.01,max=25"`
+	Terminal string            `emv:"62.07"`
+	Extra    map[string]string `emv:"62.50-99,max=5"`
+	Currency testCode          `emv:"53"`
+	Country  string            `emv:"58,len=2,format=A"`
+	Names    testName          `emv:",inline"`
+	Note     string            `emv:"-"`
+	Token    *testAccount      `emv:"30"`
+	internal string
+}
+
+func testPayloadValue() testPayload {
+	return testPayload{
+		Version:  "01",
+		Account:  testAccount{GUID: "A000000677010111", ID: "0066812345678"},
+		Bill:     "INV1",
+		Terminal: "T1",
+		Extra:    map[string]string{"50": "X", "99": "last"},
+		Currency: 764,
+		Country:  "TH",
+		Names:    testName{Name: "Shop", City: "Bangkok"},
+	}
+}
+
+const testPayloadEMV = "000201" +
+	"29370016A00000067701011101130066812345678" +
+	"5303764" +
+	"5802TH" +
+	"5904Shop" +
+	"6007Bangkok" +
+	"62270104INV10702T15001X9904last"
+
+func TestMarshalStruct(t *testing.T) {
+	p := testPayloadValue()
+	p.Note, p.internal = "left out", "left out"
+	got, err := Marshal(&p)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if got != testPayloadEMV {
+		t.Errorf("Marshal() = %s, want %s", got, testPayloadEMV)
+	}
+	if byValue, _ := Marshal(p); byValue != got {
+		t.Errorf("Marshal(value) = %s, want %s", byValue, got)
+	}
+
+	var back testPayload
+	if err := Unmarshal(got, &back); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if want := testPayloadValue(); !reflect.DeepEqual(back, want) {
+		t.Errorf("Unmarshal() = %+v, want %+v", back, want)
+	}
+
+	p.Token = &testAccount{GUID: "TOKEN"}
+	got, _ = Marshal(p)
+	if !strings.Contains(got, "30090005TOKEN") {
+		t.Errorf("Marshal() = %s, want template 30 from the pointer field", got)
+	}
+	back = testPayload{}
+	if err := Unmarshal(got, &back); err != nil || back.Token == nil || back.Token.GUID != "TOKEN" {
+		t.Errorf("Unmarshal() Token = %+v, %v, want GUID TOKEN", back.Token, err)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	long := strings.Repeat("x", 95)
+	tests := []struct {
+		name   string
+		change func(*testPayload)
+		path   string
+		err    error
+	}{
+		{"length", func(p *testPayload) { p.Version = "1" }, "00", ErrBadLength},
+		{"format N", func(p *testPayload) { p.Version = "0A" }, "00", ErrBadValue},
+		{"format A", func(p *testPayload) { p.Country = "T1" }, "58", ErrBadValue},
+		{"max", func(p *testPayload) { p.Names.City = "Krung Thep Maha Nakhon" }, "60", ErrBadLength},
+		{"template", func(p *testPayload) { p.Account.ID = "12" }, "29.01", ErrBadLength},
+		{"range tag", func(p *testPayload) { p.Extra = map[string]string{"49": "X"} }, "62.49", ErrBadTag},
+		{"range value", func(p *testPayload) { p.Extra = map[string]string{"50": "toolong"} }, "62.50", ErrBadLength},
+		{"over 99", func(p *testPayload) { p.Terminal = long }, "62", ErrBadLength},
+		{"first of several", func(p *testPayload) {
+			p.Extra = map[string]string{"98": "toolong", "55": "toolong", "70": "toolong", "51": "X"}
+		}, "62.55", ErrBadLength},
+	}
+	for _, tt := range tests {
+		// map order varies between runs, the first error must not
+		for i := 0; i < 10; i++ {
+			p := testPayloadValue()
+			tt.change(&p)
+			_, err := Marshal(p)
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Path != tt.path || !errors.Is(err, tt.err) {
+				t.Errorf("%s: Marshal() error = %v, want %v at %s", tt.name, err, tt.err, tt.path)
+				break
+			}
+		}
+	}
+
+	p := testPayloadValue()
+	p.Currency = -1
+	if _, err := Marshal(p); err == nil || !strings.Contains(err.Error(), "Currency") {
+		t.Errorf("Marshal() error = %v, want the MarshalEMV error of Currency", err)
+	}
+	if _, err := Marshal("00"); err == nil {
+		t.Error("Marshal(string) error = nil, want an error")
+	}
+	var twice struct {
+		A string `emv:"59"`
+		B string `emv:"59"`
+	}
+	twice.A, twice.B = "a", "b"
+	if _, err := Marshal(twice); err == nil {
+		t.Error("Marshal() of a tag set twice error = nil, want an error")
+	}
+	var badTag struct {
+		A string `emv:"5"`
+	}
+	if _, err := Marshal(badTag); err == nil {
+		t.Error("Marshal() of a bad tag ID error = nil, want an error")
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		path  string
+		err   error
+		check func(testPayload) bool
+	}{
+		{"format A", "000201" + "5802T1", "58", ErrBadValue,
+			func(p testPayload) bool { return p.Country == "T1" }},
+		{"template value", "000201" + "2906010212", "29.01", ErrBadLength,
+			func(p testPayload) bool { return p.Account.ID == "12" }},
+		{"bad template", "000201" + "29040105", "29.01", ErrTruncated,
+			func(p testPayload) bool { return p.Account.ID == "" }},
+		{"unmarshaler", "000201" + "5303ABC", "53", ErrBadValue,
+			func(p testPayload) bool { return p.Currency == 0 }},
+		{"first of several", "000201" + "6233" + "5507toolong" + "7007toolong" + "9807toolong", "62.55", ErrBadLength,
+			func(p testPayload) bool { return len(p.Extra) == 3 }},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			var p testPayload
+			err := Unmarshal(tt.data, &p)
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Path != tt.path || !errors.Is(err, tt.err) {
+				t.Errorf("%s: Unmarshal() error = %v, want %v at %s", tt.name, err, tt.err, tt.path)
+				break
+			}
+			if !tt.check(p) {
+				t.Errorf("%s: Unmarshal() = %+v, values must be kept", tt.name, p)
+				break
+			}
+		}
+	}
+
+	var p testPayload
+	if err := Unmarshal(testPayloadEMV, p); err == nil {
+		t.Error("Unmarshal(struct) error = nil, want an error")
+	}
+	if err := Unmarshal(testPayloadEMV, (*testPayload)(nil)); err == nil {
+		t.Error("Unmarshal(nil) error = nil, want an error")
+	}
+}
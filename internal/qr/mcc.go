@@ -0,0 +1,59 @@
+package qr
+
+import (
+	"sort"
+)
+
+// MCC is an ISO 18245 Merchant Category Code (52)
+type MCC struct {
+	Code          string // e.g. "5814"
+	Description   string
+	DescriptionTH string
+}
+
+// String formats the code with its description, such as "5814 – Fast Food Restaurants"
+func (m MCC) String() string {
+	if m.Description == "" {
+		return m.Code
+	}
+	return m.Code + " – " + m.Description
+}
+
+// mccRange is a block of codes sharing one description, such as the airline codes 3000-3350
+type mccRange struct {
+	From, To      string
+	Description   string
+	DescriptionTH string
+}
+
+var mccIndex = map[string]MCC{}
+
+func init() {
+	for _, m := range mccTable {
+		mccIndex[m.Code] = m
+	}
+}
+
+// LookupMCC returns the Merchant Category Code with its description
+func LookupMCC(code string) (MCC, bool) {
+	if m, ok := mccIndex[code]; ok {
+		return m, true
+	}
+	if len(code) != 4 || !IsDigits(code) {
+		return MCC{}, false
+	}
+	for _, r := range mccRanges {
+		if code >= r.From && code <= r.To {
+			return MCC{Code: code, Description: r.Description, DescriptionTH: r.DescriptionTH}, true
+		}
+	}
+	return MCC{}, false
+}
+
+// MCCs returns every individually listed Merchant Category Code in ascending order,
+// the airline, car rental and hotel blocks are not expanded
+func MCCs() []MCC {
+	l := append([]MCC(nil), mccTable...)
+	sort.Slice(l, func(i, j int) bool { return l[i].Code < l[j].Code })
+	return l
+}
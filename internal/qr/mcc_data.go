@@ -0,0 +1,301 @@
+package qr
+
+// mccTable is the ISO 18245 Merchant Category Codes with English and Thai descriptions
+var mccTable = []MCC{
+	{"0742", "Veterinary Services", "บริการสัตวแพทย์"},
+	{"0763", "Agricultural Cooperatives", "สหกรณ์การเกษตร"},
+	{"0780", "Landscaping and Horticultural Services", "บริการจัดสวนและพืชสวน"},
+	{"1520", "General Contractors - Residential and Commercial", "ผู้รับเหมาก่อสร้างอาคารที่พักอาศัยและพาณิชย์"},
+	{"1711", "Heating, Plumbing and Air-Conditioning Contractors", "ผู้รับเหมางานระบบทำความร้อน ประปา และปรับอากาศ"},
+	{"1731", "Electrical Contractors", "ผู้รับเหมางานไฟฟ้า"},
+	{"1740", "Masonry, Stonework, Tile-Setting, Plastering and Insulation Contractors", "ผู้รับเหมางานก่ออิฐ หิน กระเบื้อง และฉาบปูน"},
+	{"1750", "Carpentry Contractors", "ผู้รับเหมางานช่างไม้"},
+	{"1761", "Roofing, Siding and Sheet Metal Work Contractors", "ผู้รับเหมางานหลังคาและโลหะแผ่น"},
+	{"1771", "Concrete Work Contractors", "ผู้รับเหมางานคอนกรีต"},
+	{"1799", "Special Trade Contractors", "ผู้รับเหมางานเฉพาะทาง"},
+	{"2741", "Miscellaneous Publishing and Printing", "สิ่งพิมพ์และการพิมพ์เบ็ดเตล็ด"},
+	{"2791", "Typesetting, Plate Making and Related Services", "บริการเรียงพิมพ์และทำแม่พิมพ์"},
+	{"2842", "Specialty Cleaning, Polishing and Sanitation Preparations", "ผลิตภัณฑ์ทำความสะอาดและขัดเงาเฉพาะทาง"},
+	{"4011", "Railroads", "การขนส่งทางรถไฟ"},
+	{"4111", "Local and Suburban Commuter Passenger Transportation", "การขนส่งผู้โดยสารในเมืองและชานเมือง"},
+	{"4112", "Passenger Railways", "รถไฟโดยสาร"},
+	{"4119", "Ambulance Services", "บริการรถพยาบาล"},
+	{"4121", "Taxicabs and Limousines", "แท็กซี่และรถลีมูซีน"},
+	{"4131", "Bus Lines", "รถโดยสารประจำทาง"},
+	{"4214", "Motor Freight Carriers and Trucking", "การขนส่งสินค้าทางรถบรรทุก"},
+	{"4215", "Courier Services", "บริการรับส่งพัสดุ"},
+	{"4225", "Public Warehousing and Storage", "คลังสินค้าและบริการเก็บรักษา"},
+	{"4411", "Steamship and Cruise Lines", "เรือโดยสารและเรือสำราญ"},
+	{"4457", "Boat Rentals and Leasing", "บริการเช่าเรือ"},
+	{"4468", "Marinas, Marine Service and Supplies", "ท่าจอดเรือและบริการทางทะเล"},
+	{"4511", "Airlines and Air Carriers", "สายการบินและการขนส่งทางอากาศ"},
+	{"4582", "Airports, Flying Fields and Airport Terminals", "ท่าอากาศยานและอาคารผู้โดยสาร"},
+	{"4722", "Travel Agencies and Tour Operators", "บริษัทนำเที่ยวและตัวแทนท่องเที่ยว"},
+	{"4784", "Tolls and Bridge Fees", "ค่าผ่านทางและค่าข้ามสะพาน"},
+	{"4789", "Transportation Services", "บริการขนส่ง"},
+	{"4812", "Telecommunication Equipment and Telephone Sales", "อุปกรณ์โทรคมนาคมและโทรศัพท์"},
+	{"4814", "Telecommunication Services", "บริการโทรคมนาคม"},
+	{"4816", "Computer Network and Information Services", "บริการเครือข่ายคอมพิวเตอร์และข้อมูล"},
+	{"4821", "Telegraph Services", "บริการโทรเลข"},
+	{"4829", "Wire Transfers and Money Orders", "บริการโอนเงินและธนาณัติ"},
+	{"4899", "Cable, Satellite and Other Pay Television and Radio Services", "บริการเคเบิลทีวี ดาวเทียม และวิทยุแบบบอกรับสมาชิก"},
+	{"4900", "Utilities - Electric, Gas, Water and Sanitary", "สาธารณูปโภค ไฟฟ้า ก๊าซ ประปา และสุขาภิบาล"},
+	{"5013", "Motor Vehicle Supplies and New Parts", "อุปกรณ์และอะไหล่ยานยนต์"},
+	{"5021", "Office and Commercial Furniture", "เฟอร์นิเจอร์สำนักงานและเชิงพาณิชย์"},
+	{"5039", "Construction Materials", "วัสดุก่อสร้าง"},
+	{"5044", "Photographic, Photocopy, Microfilm Equipment and Supplies", "อุปกรณ์ถ่ายภาพและถ่ายเอกสาร"},
+	{"5045", "Computers and Computer Peripheral Equipment and Software", "คอมพิวเตอร์ อุปกรณ์ต่อพ่วง และซอฟต์แวร์"},
+	{"5046", "Commercial Equipment", "อุปกรณ์เชิงพาณิชย์"},
+	{"5047", "Medical, Dental, Ophthalmic and Hospital Equipment and Supplies", "อุปกรณ์การแพทย์ ทันตกรรม และโรงพยาบาล"},
+	{"5051", "Metal Service Centers and Offices", "ศูนย์บริการโลหะ"},
+	{"5065", "Electrical Parts and Equipment", "ชิ้นส่วนและอุปกรณ์ไฟฟ้า"},
+	{"5072", "Hardware, Equipment and Supplies", "ฮาร์ดแวร์และอุปกรณ์เครื่องมือ"},
+	{"5074", "Plumbing and Heating Equipment and Supplies", "อุปกรณ์ประปาและระบบทำความร้อน"},
+	{"5085", "Industrial Supplies", "วัสดุอุตสาหกรรม"},
+	{"5094", "Precious Stones and Metals, Watches and Jewelry", "อัญมณี โลหะมีค่า นาฬิกา และเครื่องประดับ"},
+	{"5099", "Durable Goods", "สินค้าคงทน"},
+	{"5111", "Stationery, Office Supplies, Printing and Writing Paper", "เครื่องเขียนและอุปกรณ์สำนักงาน"},
+	{"5122", "Drugs, Drug Proprietaries and Druggist Sundries", "ยาและเวชภัณฑ์"},
+	{"5131", "Piece Goods, Notions and Other Dry Goods", "ผ้าและสินค้าเบ็ดเตล็ด"},
+	{"5137", "Men's, Women's and Children's Uniforms and Commercial Clothing", "เครื่องแบบและเสื้อผ้าเชิงพาณิชย์"},
+	{"5139", "Commercial Footwear", "รองเท้าเชิงพาณิชย์"},
+	{"5169", "Chemicals and Allied Products", "เคมีภัณฑ์และผลิตภัณฑ์ที่เกี่ยวข้อง"},
+	{"5172", "Petroleum and Petroleum Products", "ปิโตรเลียมและผลิตภัณฑ์ปิโตรเลียม"},
+	{"5192", "Books, Periodicals and Newspapers", "หนังสือ วารสาร และหนังสือพิมพ์"},
+	{"5193", "Florists' Supplies, Nursery Stock and Flowers", "อุปกรณ์ร้านดอกไม้ ต้นไม้ และดอกไม้"},
+	{"5198", "Paints, Varnishes and Supplies", "สี น้ำมันเคลือบเงา และอุปกรณ์"},
+	{"5199", "Nondurable Goods", "สินค้าไม่คงทน"},
+	{"5200", "Home Supply Warehouse Stores", "ร้านค้าวัสดุตกแต่งบ้านขนาดใหญ่"},
+	{"5211", "Lumber and Building Materials Stores", "ร้านไม้และวัสดุก่อสร้าง"},
+	{"5231", "Glass, Paint and Wallpaper Stores", "ร้านกระจก สี และวอลเปเปอร์"},
+	{"5251", "Hardware Stores", "ร้านฮาร์ดแวร์"},
+	{"5261", "Lawn and Garden Supply Stores", "ร้านอุปกรณ์สนามหญ้าและสวน"},
+	{"5271", "Mobile Home Dealers", "ตัวแทนจำหน่ายบ้านเคลื่อนที่"},
+	{"5300", "Wholesale Clubs", "คลับค้าส่ง"},
+	{"5309", "Duty Free Stores", "ร้านค้าปลอดภาษี"},
+	{"5310", "Discount Stores", "ร้านค้าลดราคา"},
+	{"5311", "Department Stores", "ห้างสรรพสินค้า"},
+	{"5331", "Variety Stores", "ร้านค้าเบ็ดเตล็ด"},
+	{"5399", "Miscellaneous General Merchandise", "สินค้าทั่วไปเบ็ดเตล็ด"},
+	{"5411", "Grocery Stores and Supermarkets", "ร้านขายของชำและซูเปอร์มาร์เก็ต"},
+	{"5422", "Freezer and Locker Meat Provisioners", "ร้านจำหน่ายเนื้อสัตว์แช่แข็ง"},
+	{"5441", "Candy, Nut and Confectionery Stores", "ร้านขนม ถั่ว และลูกกวาด"},
+	{"5451", "Dairy Products Stores", "ร้านผลิตภัณฑ์นม"},
+	{"5462", "Bakeries", "ร้านเบเกอรี่"},
+	{"5499", "Miscellaneous Food Stores - Convenience Stores and Specialty Markets", "ร้านอาหารเบ็ดเตล็ด ร้านสะดวกซื้อ และตลาดเฉพาะทาง"},
+	{"5511", "Car and Truck Dealers - New and Used", "ตัวแทนจำหน่ายรถยนต์และรถบรรทุกใหม่และมือสอง"},
+	{"5521", "Car and Truck Dealers - Used Only", "ตัวแทนจำหน่ายรถยนต์และรถบรรทุกมือสอง"},
+	{"5531", "Auto and Home Supply Stores", "ร้านอุปกรณ์รถยนต์และของใช้ในบ้าน"},
+	{"5532", "Automotive Tire Stores", "ร้านยางรถยนต์"},
+	{"5533", "Automotive Parts and Accessories Stores", "ร้านอะไหล่และอุปกรณ์ตกแต่งรถยนต์"},
+	{"5541", "Service Stations", "สถานีบริการน้ำมัน"},
+	{"5542", "Automated Fuel Dispensers", "ตู้จ่ายน้ำมันอัตโนมัติ"},
+	{"5551", "Boat Dealers", "ตัวแทนจำหน่ายเรือ"},
+	{"5561", "Camper, Recreational and Utility Trailer Dealers", "ตัวแทนจำหน่ายรถบ้านและรถพ่วง"},
+	{"5571", "Motorcycle Shops and Dealers", "ร้านและตัวแทนจำหน่ายรถจักรยานยนต์"},
+	{"5592", "Motor Home Dealers", "ตัวแทนจำหน่ายรถบ้าน"},
+	{"5598", "Snowmobile Dealers", "ตัวแทนจำหน่ายสโนว์โมบิล"},
+	{"5599", "Miscellaneous Automotive, Aircraft and Farm Equipment Dealers", "ตัวแทนจำหน่ายยานยนต์ อากาศยาน และเครื่องจักรการเกษตร"},
+	{"5611", "Men's and Boys' Clothing and Accessories Stores", "ร้านเสื้อผ้าและเครื่องประดับบุรุษ"},
+	{"5621", "Women's Ready-to-Wear Stores", "ร้านเสื้อผ้าสำเร็จรูปสตรี"},
+	{"5631", "Women's Accessory and Specialty Shops", "ร้านเครื่องประดับและสินค้าเฉพาะทางสตรี"},
+	{"5641", "Children's and Infants' Wear Stores", "ร้านเสื้อผ้าเด็กและทารก"},
+	{"5651", "Family Clothing Stores", "ร้านเสื้อผ้าครอบครัว"},
+	{"5655", "Sports and Riding Apparel Stores", "ร้านเสื้อผ้ากีฬาและขี่ม้า"},
+	{"5661", "Shoe Stores", "ร้านรองเท้า"},
+	{"5681", "Furriers and Fur Shops", "ร้านขนสัตว์"},
+	{"5691", "Men's and Women's Clothing Stores", "ร้านเสื้อผ้าบุรุษและสตรี"},
+	{"5697", "Tailors, Seamstresses, Mending and Alterations", "ร้านตัดเย็บและซ่อมแซมเสื้อผ้า"},
+	{"5698", "Wig and Toupee Stores", "ร้านวิกผม"},
+	{"5699", "Miscellaneous Apparel and Accessory Shops", "ร้านเสื้อผ้าและเครื่องประดับเบ็ดเตล็ด"},
+	{"5712", "Furniture, Home Furnishings and Equipment Stores", "ร้านเฟอร์นิเจอร์และของตกแต่งบ้าน"},
+	{"5713", "Floor Covering Stores", "ร้านวัสดุปูพื้น"},
+	{"5714", "Drapery, Window Covering and Upholstery Stores", "ร้านผ้าม่านและวัสดุหุ้มเบาะ"},
+	{"5718", "Fireplace, Fireplace Screens and Accessories Stores", "ร้านเตาผิงและอุปกรณ์"},
+	{"5719", "Miscellaneous Home Furnishing Specialty Stores", "ร้านของตกแต่งบ้านเฉพาะทาง"},
+	{"5722", "Household Appliance Stores", "ร้านเครื่องใช้ไฟฟ้าในบ้าน"},
+	{"5732", "Electronics Stores", "ร้านอุปกรณ์อิเล็กทรอนิกส์"},
+	{"5733", "Music Stores - Musical Instruments, Pianos and Sheet Music", "ร้านเครื่องดนตรีและโน้ตเพลง"},
+	{"5734", "Computer Software Stores", "ร้านซอฟต์แวร์คอมพิวเตอร์"},
+	{"5735", "Record Stores", "ร้านแผ่นเสียง"},
+	{"5811", "Caterers", "บริการจัดเลี้ยง"},
+	{"5812", "Eating Places and Restaurants", "ร้านอาหารและภัตตาคาร"},
+	{"5813", "Drinking Places - Bars, Taverns, Nightclubs and Discotheques", "สถานบริการเครื่องดื่ม บาร์ และไนต์คลับ"},
+	{"5814", "Fast Food Restaurants", "ร้านอาหารจานด่วน"},
+	{"5815", "Digital Goods - Media, Books, Movies and Music", "สินค้าดิจิทัล สื่อ หนังสือ ภาพยนตร์ และเพลง"},
+	{"5816", "Digital Goods - Games", "สินค้าดิจิทัล เกม"},
+	{"5817", "Digital Goods - Applications", "สินค้าดิจิทัล แอปพลิเคชัน"},
+	{"5818", "Digital Goods - Large Digital Goods Merchant", "สินค้าดิจิทัล ผู้ค้ารายใหญ่"},
+	{"5912", "Drug Stores and Pharmacies", "ร้านขายยาและเภสัชกรรม"},
+	{"5921", "Package Stores - Beer, Wine and Liquor", "ร้านจำหน่ายเบียร์ ไวน์ และสุรา"},
+	{"5931", "Used Merchandise and Secondhand Stores", "ร้านสินค้ามือสอง"},
+	{"5932", "Antique Shops", "ร้านของเก่า"},
+	{"5933", "Pawn Shops", "โรงรับจำนำ"},
+	{"5935", "Wrecking and Salvage Yards", "ลานรถเก่าและเศษวัสดุ"},
+	{"5937", "Antique Reproductions", "ร้านของเก่าจำลอง"},
+	{"5940", "Bicycle Shops", "ร้านจักรยาน"},
+	{"5941", "Sporting Goods Stores", "ร้านอุปกรณ์กีฬา"},
+	{"5942", "Book Stores", "ร้านหนังสือ"},
+	{"5943", "Stationery, Office and School Supply Stores", "ร้านเครื่องเขียนและอุปกรณ์การเรียน"},
+	{"5944", "Jewelry, Watch, Clock and Silverware Stores", "ร้านเครื่องประดับ นาฬิกา และเครื่องเงิน"},
+	{"5945", "Hobby, Toy and Game Shops", "ร้านของเล่นและเกม"},
+	{"5946", "Camera and Photographic Supply Stores", "ร้านกล้องและอุปกรณ์ถ่ายภาพ"},
+	{"5947", "Gift, Card, Novelty and Souvenir Shops", "ร้านของขวัญและของที่ระลึก"},
+	{"5948", "Luggage and Leather Goods Stores", "ร้านกระเป๋าเดินทางและเครื่องหนัง"},
+	{"5949", "Sewing, Needlework, Fabric and Piece Goods Stores", "ร้านผ้าและอุปกรณ์เย็บปักถักร้อย"},
+	{"5950", "Glassware and Crystal Stores", "ร้านเครื่องแก้วและคริสตัล"},
+	{"5960", "Direct Marketing - Insurance Services", "การตลาดทางตรง บริการประกันภัย"},
+	{"5961", "Mail Order Houses", "ร้านค้าทางไปรษณีย์"},
+	{"5962", "Direct Marketing - Travel-Related Arrangement Services", "การตลาดทางตรง บริการด้านการเดินทาง"},
+	{"5963", "Door-to-Door Sales", "การขายตรงถึงบ้าน"},
+	{"5964", "Direct Marketing - Catalog Merchant", "การตลาดทางตรง ขายผ่านแคตตาล็อก"},
+	{"5965", "Direct Marketing - Combination Catalog and Retail Merchant", "การตลาดทางตรง แคตตาล็อกและค้าปลีก"},
+	{"5966", "Direct Marketing - Outbound Telemarketing Merchant", "การตลาดทางตรง เทเลมาร์เก็ตติ้งขาออก"},
+	{"5967", "Direct Marketing - Inbound Teleservices Merchant", "การตลาดทางตรง บริการทางโทรศัพท์ขาเข้า"},
+	{"5968", "Direct Marketing - Continuity and Subscription Merchant", "การตลาดทางตรง สมาชิกแบบต่อเนื่อง"},
+	{"5969", "Direct Marketing - Other Direct Marketers", "การตลาดทางตรงอื่น ๆ"},
+	{"5970", "Artist's Supply and Craft Shops", "ร้านอุปกรณ์ศิลปะและงานฝีมือ"},
+	{"5971", "Art Dealers and Galleries", "ตัวแทนจำหน่ายงานศิลปะและแกลเลอรี"},
+	{"5972", "Stamp and Coin Stores", "ร้านแสตมป์และเหรียญ"},
+	{"5973", "Religious Goods Stores", "ร้านสินค้าทางศาสนา"},
+	{"5975", "Hearing Aids - Sales, Service and Supplies", "เครื่องช่วยฟัง จำหน่ายและบริการ"},
+	{"5976", "Orthopedic Goods and Prosthetic Devices", "อุปกรณ์กระดูกและอวัยวะเทียม"},
+	{"5977", "Cosmetic Stores", "ร้านเครื่องสำอาง"},
+	{"5978", "Typewriter Stores - Sales, Rental and Service", "ร้านเครื่องพิมพ์ดีด"},
+	{"5983", "Fuel Dealers - Fuel Oil, Wood, Coal and Liquefied Petroleum", "ตัวแทนจำหน่ายเชื้อเพลิง"},
+	{"5992", "Florists", "ร้านดอกไม้"},
+	{"5993", "Cigar Stores and Stands", "ร้านซิการ์และยาสูบ"},
+	{"5994", "News Dealers and Newsstands", "ร้านและแผงหนังสือพิมพ์"},
+	{"5995", "Pet Shops, Pet Food and Supplies", "ร้านสัตว์เลี้ยง อาหาร และอุปกรณ์"},
+	{"5996", "Swimming Pools - Sales and Service", "สระว่ายน้ำ จำหน่ายและบริการ"},
+	{"5997", "Electric Razor Stores - Sales and Service", "ร้านเครื่องโกนหนวดไฟฟ้า"},
+	{"5998", "Tent and Awning Shops", "ร้านเต็นท์และกันสาด"},
+	{"5999", "Miscellaneous and Specialty Retail Stores", "ร้านค้าปลีกเบ็ดเตล็ดและเฉพาะทาง"},
+	{"6010", "Financial Institutions - Manual Cash Disbursements", "สถาบันการเงิน การจ่ายเงินสดผ่านพนักงาน"},
+	{"6011", "Financial Institutions - Automated Cash Disbursements", "สถาบันการเงิน การจ่ายเงินสดผ่านเครื่องอัตโนมัติ"},
+	{"6012", "Financial Institutions - Merchandise and Services", "สถาบันการเงิน สินค้าและบริการ"},
+	{"6050", "Quasi Cash - Financial Institutions", "ธุรกรรมกึ่งเงินสด สถาบันการเงิน"},
+	{"6051", "Non-Financial Institutions - Foreign Currency, Money Orders and Travelers' Cheques", "สถาบันที่ไม่ใช่สถาบันการเงิน แลกเปลี่ยนเงินตราต่างประเทศ"},
+	{"6211", "Security Brokers and Dealers", "นายหน้าและผู้ค้าหลักทรัพย์"},
+	{"6300", "Insurance Sales, Underwriting and Premiums", "การขายและรับประกันภัย"},
+	{"6513", "Real Estate Agents and Managers - Rentals", "ตัวแทนและผู้จัดการอสังหาริมทรัพย์ ค่าเช่า"},
+	{"6540", "Non-Financial Institutions - Stored Value Card Purchase and Load", "สถาบันที่ไม่ใช่สถาบันการเงิน การซื้อและเติมเงินบัตร"},
+	{"7011", "Lodging - Hotels, Motels and Resorts", "ที่พัก โรงแรม โมเต็ล และรีสอร์ท"},
+	{"7012", "Timeshares", "ที่พักแบบไทม์แชร์"},
+	{"7032", "Sporting and Recreational Camps", "ค่ายกีฬาและนันทนาการ"},
+	{"7033", "Trailer Parks and Campgrounds", "ลานจอดรถพ่วงและลานกางเต็นท์"},
+	{"7210", "Laundry, Cleaning and Garment Services", "บริการซักรีดและทำความสะอาดเสื้อผ้า"},
+	{"7211", "Laundries - Family and Commercial", "ร้านซักรีด"},
+	{"7216", "Dry Cleaners", "ร้านซักแห้ง"},
+	{"7217", "Carpet and Upholstery Cleaning", "บริการทำความสะอาดพรมและเบาะ"},
+	{"7221", "Photographic Studios", "สตูดิโอถ่ายภาพ"},
+	{"7230", "Beauty and Barber Shops", "ร้านเสริมสวยและตัดผม"},
+	{"7251", "Shoe Repair Shops, Shoe Shine Parlors and Hat Cleaning Shops", "ร้านซ่อมรองเท้าและขัดรองเท้า"},
+	{"7261", "Funeral Services and Crematories", "บริการจัดงานศพและฌาปนสถาน"},
+	{"7273", "Dating and Escort Services", "บริการหาคู่"},
+	{"7276", "Tax Preparation Services", "บริการจัดทำภาษี"},
+	{"7277", "Counseling Services - Debt, Marriage and Personal", "บริการให้คำปรึกษา"},
+	{"7278", "Buying and Shopping Services and Clubs", "บริการและคลับช่วยซื้อสินค้า"},
+	{"7296", "Clothing Rental - Costumes, Uniforms and Formal Wear", "บริการเช่าชุด"},
+	{"7297", "Massage Parlors", "ร้านนวด"},
+	{"7298", "Health and Beauty Spas", "สปาเพื่อสุขภาพและความงาม"},
+	{"7299", "Miscellaneous Personal Services", "บริการส่วนบุคคลเบ็ดเตล็ด"},
+	{"7311", "Advertising Services", "บริการโฆษณา"},
+	{"7321", "Consumer Credit Reporting Agencies", "หน่วยงานรายงานข้อมูลเครดิต"},
+	{"7333", "Commercial Photography, Art and Graphics", "การถ่ายภาพเชิงพาณิชย์ ศิลปะ และกราฟิก"},
+	{"7338", "Quick Copy, Reproduction and Blueprinting Services", "บริการถ่ายเอกสารและพิมพ์เขียว"},
+	{"7339", "Stenographic and Secretarial Support Services", "บริการเลขานุการและชวเลข"},
+	{"7342", "Exterminating and Disinfecting Services", "บริการกำจัดแมลงและฆ่าเชื้อ"},
+	{"7349", "Cleaning, Maintenance and Janitorial Services", "บริการทำความสะอาดและซ่อมบำรุง"},
+	{"7361", "Employment Agencies and Temporary Help Services", "บริษัทจัดหางานและพนักงานชั่วคราว"},
+	{"7372", "Computer Programming, Data Processing and Integrated Systems Design Services", "บริการเขียนโปรแกรม ประมวลผลข้อมูล และออกแบบระบบ"},
+	{"7375", "Information Retrieval Services", "บริการค้นหาข้อมูล"},
+	{"7379", "Computer Maintenance, Repair and Services", "บริการซ่อมบำรุงคอมพิวเตอร์"},
+	{"7392", "Management, Consulting and Public Relations Services", "บริการบริหาร ที่ปรึกษา และประชาสัมพันธ์"},
+	{"7393", "Detective Agencies, Protective Agencies and Security Services", "บริการนักสืบและรักษาความปลอดภัย"},
+	{"7394", "Equipment, Tool, Furniture and Appliance Rental and Leasing", "บริการเช่าอุปกรณ์ เครื่องมือ และเฟอร์นิเจอร์"},
+	{"7395", "Photofinishing Laboratories and Photo Developing", "ห้องแล็บล้างและอัดรูป"},
+	{"7399", "Business Services", "บริการธุรกิจ"},
+	{"7512", "Car Rental Agencies", "บริการรถเช่า"},
+	{"7513", "Truck and Utility Trailer Rentals", "บริการเช่ารถบรรทุกและรถพ่วง"},
+	{"7519", "Motor Home and Recreational Vehicle Rentals", "บริการเช่ารถบ้าน"},
+	{"7523", "Parking Lots, Parking Meters and Garages", "ลานจอดรถและที่จอดรถ"},
+	{"7531", "Automotive Body Repair Shops", "อู่ซ่อมตัวถังรถยนต์"},
+	{"7534", "Tire Retreading and Repair Shops", "ร้านหล่อดอกและซ่อมยาง"},
+	{"7535", "Automotive Paint Shops", "อู่พ่นสีรถยนต์"},
+	{"7538", "Automotive Service Shops", "ศูนย์บริการรถยนต์"},
+	{"7542", "Car Washes", "ร้านล้างรถ"},
+	{"7549", "Towing Services", "บริการรถยก"},
+	{"7622", "Electronics Repair Shops", "ร้านซ่อมอุปกรณ์อิเล็กทรอนิกส์"},
+	{"7623", "Air Conditioning and Refrigeration Repair Shops", "ร้านซ่อมเครื่องปรับอากาศและตู้เย็น"},
+	{"7629", "Electrical and Small Appliance Repair Shops", "ร้านซ่อมเครื่องใช้ไฟฟ้าขนาดเล็ก"},
+	{"7631", "Watch, Clock and Jewelry Repair Shops", "ร้านซ่อมนาฬิกาและเครื่องประดับ"},
+	{"7641", "Furniture Reupholstery, Repair and Refinishing", "ร้านซ่อมและหุ้มเบาะเฟอร์นิเจอร์"},
+	{"7692", "Welding Services", "บริการงานเชื่อม"},
+	{"7699", "Miscellaneous Repair Shops and Related Services", "ร้านซ่อมเบ็ดเตล็ด"},
+	{"7800", "Government-Owned Lotteries", "สลากกินแบ่งรัฐบาล"},
+	{"7801", "Government Licensed On-Line Casinos", "คาสิโนออนไลน์ที่รัฐอนุญาต"},
+	{"7802", "Government Licensed Horse and Dog Racing", "การแข่งม้าและสุนัขที่รัฐอนุญาต"},
+	{"7829", "Motion Picture and Video Tape Production and Distribution", "การผลิตและจัดจำหน่ายภาพยนตร์และวิดีโอ"},
+	{"7832", "Motion Picture Theaters", "โรงภาพยนตร์"},
+	{"7841", "Video Tape Rental Stores", "ร้านเช่าวิดีโอ"},
+	{"7911", "Dance Halls, Studios and Schools", "สถานลีลาศและโรงเรียนสอนเต้นรำ"},
+	{"7922", "Theatrical Producers and Ticket Agencies", "ผู้จัดการแสดงและตัวแทนจำหน่ายตั๋ว"},
+	{"7929", "Bands, Orchestras and Miscellaneous Entertainers", "วงดนตรีและนักแสดงเบ็ดเตล็ด"},
+	{"7932", "Billiard and Pool Establishments", "สถานบริการบิลเลียด"},
+	{"7933", "Bowling Alleys", "สถานบริการโบว์ลิ่ง"},
+	{"7941", "Commercial Sports, Professional Sports Clubs and Athletic Fields", "กีฬาเชิงพาณิชย์และสโมสรกีฬาอาชีพ"},
+	{"7991", "Tourist Attractions and Exhibits", "สถานที่ท่องเที่ยวและนิทรรศการ"},
+	{"7992", "Public Golf Courses", "สนามกอล์ฟสาธารณะ"},
+	{"7993", "Video Amusement Game Supplies", "อุปกรณ์เกมตู้"},
+	{"7994", "Video Game Arcades and Establishments", "ร้านเกมตู้"},
+	{"7995", "Betting, Including Lottery Tickets, Casino Gaming Chips and Off-Track Betting", "การพนัน สลาก และชิปคาสิโน"},
+	{"7996", "Amusement Parks, Circuses, Carnivals and Fortune Tellers", "สวนสนุก ละครสัตว์ และหมอดู"},
+	{"7997", "Membership Clubs - Sports, Recreation, Athletic, Country Clubs and Private Golf Courses", "สโมสรสมาชิกกีฬาและนันทนาการ"},
+	{"7998", "Aquariums, Seaquariums and Dolphinariums", "พิพิธภัณฑ์สัตว์น้ำ"},
+	{"7999", "Recreation Services", "บริการนันทนาการ"},
+	{"8011", "Doctors and Physicians", "แพทย์"},
+	{"8021", "Dentists and Orthodontists", "ทันตแพทย์และทันตแพทย์จัดฟัน"},
+	{"8031", "Osteopaths", "แพทย์ออสทีโอพาธี"},
+	{"8041", "Chiropractors", "แพทย์ไคโรแพรคติก"},
+	{"8042", "Optometrists and Ophthalmologists", "นักทัศนมาตรและจักษุแพทย์"},
+	{"8043", "Opticians, Optical Goods and Eyeglasses", "ร้านแว่นตาและอุปกรณ์สายตา"},
+	{"8049", "Podiatrists and Chiropodists", "แพทย์โรคเท้า"},
+	{"8050", "Nursing and Personal Care Facilities", "สถานพยาบาลและดูแลผู้ป่วย"},
+	{"8062", "Hospitals", "โรงพยาบาล"},
+	{"8071", "Medical and Dental Laboratories", "ห้องปฏิบัติการทางการแพทย์และทันตกรรม"},
+	{"8099", "Medical Services and Health Practitioners", "บริการทางการแพทย์และผู้ประกอบวิชาชีพด้านสุขภาพ"},
+	{"8111", "Legal Services and Attorneys", "บริการกฎหมายและทนายความ"},
+	{"8211", "Elementary and Secondary Schools", "โรงเรียนประถมศึกษาและมัธยมศึกษา"},
+	{"8220", "Colleges, Universities, Professional Schools and Junior Colleges", "วิทยาลัยและมหาวิทยาลัย"},
+	{"8241", "Correspondence Schools", "โรงเรียนทางไปรษณีย์"},
+	{"8244", "Business and Secretarial Schools", "โรงเรียนธุรกิจและเลขานุการ"},
+	{"8249", "Vocational and Trade Schools", "โรงเรียนอาชีวศึกษา"},
+	{"8299", "Schools and Educational Services", "โรงเรียนและบริการการศึกษา"},
+	{"8351", "Child Care Services", "บริการรับเลี้ยงเด็ก"},
+	{"8398", "Charitable and Social Service Organizations", "องค์กรการกุศลและบริการสังคม"},
+	{"8641", "Civic, Social and Fraternal Associations", "สมาคมพลเมือง สังคม และภราดรภาพ"},
+	{"8651", "Political Organizations", "องค์กรทางการเมือง"},
+	{"8661", "Religious Organizations", "องค์กรทางศาสนา"},
+	{"8675", "Automobile Associations", "สมาคมยานยนต์"},
+	{"8699", "Membership Organizations", "องค์กรสมาชิก"},
+	{"8734", "Testing Laboratories", "ห้องปฏิบัติการทดสอบ"},
+	{"8911", "Architectural, Engineering and Surveying Services", "บริการสถาปัตยกรรม วิศวกรรม และสำรวจ"},
+	{"8931", "Accounting, Auditing and Bookkeeping Services", "บริการบัญชี ตรวจสอบบัญชี และทำบัญชี"},
+	{"8999", "Professional Services", "บริการวิชาชีพ"},
+	{"9211", "Court Costs, Including Alimony and Child Support", "ค่าธรรมเนียมศาล"},
+	{"9222", "Fines", "ค่าปรับ"},
+	{"9223", "Bail and Bond Payments", "เงินประกันตัว"},
+	{"9311", "Tax Payments", "การชำระภาษี"},
+	{"9399", "Government Services", "บริการภาครัฐ"},
+	{"9402", "Postal Services - Government Only", "บริการไปรษณีย์ของรัฐ"},
+	{"9405", "Intra-Government Purchases - Government Only", "การจัดซื้อภายในภาครัฐ"},
+	{"9950", "Intra-Company Purchases", "การจัดซื้อภายในบริษัท"},
+}
+
+// mccRanges are the codes assigned to individual airlines, car rental agencies and hotels
+var mccRanges = []mccRange{
+	{"3000", "3350", "Airlines", "สายการบิน"},
+	{"3351", "3500", "Car Rental Agencies", "บริการรถเช่า"},
+	{"3501", "3999", "Lodging - Hotels, Motels and Resorts", "ที่พัก โรงแรม โมเต็ล และรีสอร์ท"},
+}
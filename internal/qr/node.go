@@ -0,0 +1,186 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/howeyc/crc16"
+)
+
+// Node is a single ID / Length / Value data object of an EMVCo payload
+type Node struct {
+	ID       string  // 2 digits
+	Value    string  // raw value as found in the payload
+	Offset   int     // byte offset of the ID from the start of the payload
+	Children []*Node // nested data objects when ID is a template
+}
+
+// Payload is an order-preserving tree of the data objects in a QR string.
+// Unknown and duplicated tags are kept as they are found.
+type Payload struct {
+	Nodes []*Node
+}
+
+// isTemplate reports whether the value of a top level tag holds nested data objects
+func isTemplate(id string) bool {
+	switch {
+	case id >= "26" && id <= "51": // Merchant Account Information
+		return true
+	case id == "62", id == "64": // Additional Data, Merchant Information Language
+		return true
+	case id >= "80" && id <= "99": // Unreserved Templates
+		return true
+	}
+	return false
+}
+
+// Parse decodes s into a tree of nodes. Template values (26-51, 62, 64, 80-99)
+// are parsed into Children when they are well formed, otherwise they are kept
+// as primitive values so nothing of the original payload is lost.
+func Parse(s string) (*Payload, error) {
+	nodes, err := parseNodes(s, 0, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		if !isTemplate(n.ID) || n.Value == "" {
+			continue
+		}
+		if children, err := parseNodes(n.Value, n.Offset+4, n.ID); err == nil {
+			n.Children = children
+		}
+	}
+	return &Payload{Nodes: nodes}, nil
+}
+
+// parseNodes reads consecutive data objects from s. base is the byte offset
+// of s in the payload and parent the tag path of the enclosing template.
+func parseNodes(s string, base int, parent string) ([]*Node, error) {
+	var nodes []*Node
+	for i := 0; i < len(s); {
+		if i+4 > len(s) {
+			return nil, &ParseError{Offset: base + i, Path: parent, Err: ErrTruncated}
+		}
+		id, ls := s[i:i+2], s[i+2:i+4]
+		path := id
+		if parent != "" {
+			path = parent + "." + id
+		}
+		if !IsDigits(id) {
+			return nil, &ParseError{Offset: base + i, Path: parent, Expected: "2 digits", Actual: id, Err: ErrBadTag}
+		}
+		if !IsDigits(ls) {
+			return nil, &ParseError{Offset: base + i + 2, Path: path, Expected: "2 digits", Actual: ls, Err: ErrBadLength}
+		}
+		l := int(ls[0]-'0')*10 + int(ls[1]-'0')
+		start := i + 4
+		end, ok := skipRunes(s, start, l)
+		if !ok {
+			return nil, &ParseError{Offset: base + i, Path: path, Expected: ls, Actual: fmt.Sprint(utf8.RuneCountInString(s[start:])), Err: ErrTruncated}
+		}
+		nodes = append(nodes, &Node{ID: id, Value: s[start:end], Offset: base + i})
+		i = end
+	}
+	return nodes, nil
+}
+
+// skipRunes returns the byte index found after n runes from s[i:]
+func skipRunes(s string, i, n int) (int, bool) {
+	for ; n > 0; n-- {
+		if i >= len(s) {
+			return i, false
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return i, true
+}
+
+// IsDigits reports whether s is a non-empty string of ASCII digits
+func IsDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// value returns the value to serialize; Children take precedence over Value
+func (n *Node) value() string {
+	if len(n.Children) == 0 {
+		return n.Value
+	}
+	var str bytes.Buffer
+	for _, c := range n.Children {
+		str.WriteString(c.String())
+	}
+	return str.String()
+}
+
+// String serializes the node as ID, 2 digits length and value
+func (n *Node) String() string {
+	v := n.value()
+	return fmt.Sprintf("%s%02d%s", n.ID, utf8.RuneCountInString(v), v)
+}
+
+// Get returns the first child with the given ID
+func (n *Node) Get(id string) *Node {
+	return findNode(n.Children, id)
+}
+
+func findNode(nodes []*Node, id string) *Node {
+	for _, n := range nodes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
+
+// String serializes the payload. An unmodified payload returns exactly the parsed string.
+func (p *Payload) String() string {
+	var str bytes.Buffer
+	for _, n := range p.Nodes {
+		str.WriteString(n.String())
+	}
+	return str.String()
+}
+
+// Get returns the first top level node with the given ID
+func (p *Payload) Get(id string) *Node {
+	return findNode(p.Nodes, id)
+}
+
+// Find returns the node at a dotted tag path such as "62.05"
+func (p *Payload) Find(path string) *Node {
+	ids := strings.Split(path, ".")
+	n := p.Get(ids[0])
+	for _, id := range ids[1:] {
+		if n == nil {
+			return nil
+		}
+		n = n.Get(id)
+	}
+	return n
+}
+
+// Sign recomputes the CRC (tag 63) over the serialized payload and moves it
+// to the end, appending it when missing.
+func (p *Payload) Sign() {
+	nodes := make([]*Node, 0, len(p.Nodes)+1)
+	for _, n := range p.Nodes {
+		if n.ID != "63" {
+			nodes = append(nodes, n)
+		}
+	}
+	p.Nodes = nodes
+	data := p.String() + "6304"
+	p.Nodes = append(p.Nodes, &Node{
+		ID:     "63",
+		Value:  fmt.Sprintf("%04X", crc16.ChecksumCCITTFalse([]byte(data))),
+		Offset: len(data) - 4,
+	})
+}
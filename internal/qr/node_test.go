@@ -0,0 +1,118 @@
+package qr
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{"Thai text", "000201010211" + "64290002TH0108ร้านกาแฟ0207กรุงเทพ" + "6304ABCD"},
+		{"unknown and duplicated tags", "000201" + "5802TH" + "5802TH" + "9803XYZ" + "7702AB"},
+		{"malformed template kept as value", "000201" + "6205ABCDE" + "6304FFFF"},
+		{"lower case CRC", strings.Replace(decoderPayloads[1].payload, "6304FF19", "6304ff19", 1)},
+		{"CRC not last", "000201" + "6304FF19" + "5802TH"},
+		{"empty value", "000201" + "5900" + "5802TH"},
+		{"template with empty sub-tag", "000201" + "6210" + "0500" + "0702T1"},
+		{"empty payload", ""},
+	}
+	for i, p := range decoderPayloads {
+		tests = append(tests, struct{ name, payload string }{fmt.Sprintf("decoder payload %d", i), p.payload})
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.payload)
+		if err != nil {
+			t.Errorf("%s: Parse(%s) error = %v", tt.name, tt.payload, err)
+			continue
+		}
+		if got := p.String(); got != tt.payload {
+			t.Errorf("%s: Parse(%s).String() = %s", tt.name, tt.payload, got)
+		}
+		var str strings.Builder
+		for _, n := range p.Nodes {
+			if tt.payload[n.Offset:n.Offset+2] != n.ID {
+				t.Errorf("%s: node %s at offset %d reads %q", tt.name, n.ID, n.Offset, tt.payload[n.Offset:n.Offset+2])
+			}
+			for _, c := range n.Children {
+				if tt.payload[c.Offset:c.Offset+2] != c.ID {
+					t.Errorf("%s: node %s.%s at offset %d reads %q", tt.name, n.ID, c.ID, c.Offset, tt.payload[c.Offset:c.Offset+2])
+				}
+			}
+			str.WriteString(n.String())
+		}
+		if str.String() != tt.payload {
+			t.Errorf("%s: nodes serialize to %s", tt.name, str.String())
+		}
+	}
+}
+
+func TestParseTree(t *testing.T) {
+	p, err := Parse(decoderPayloads[1].payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path   string
+		value  string
+		offset int
+	}{
+		{"00", "01", 0},
+		{"29", "0016A00000067701011101130066812345678", 12},
+		{"29.00", "A000000677010111", 16},
+		{"29.01", "0066812345678", 36},
+		{"54", "100.50", 68},
+		{"62.05", "ABC", 107},
+		{"63", "FF19", 114},
+	}
+	for _, tt := range tests {
+		n := p.Find(tt.path)
+		if n == nil || n.Value != tt.value || n.Offset != tt.offset {
+			t.Errorf("Find(%s) = %+v, want value %q at offset %d", tt.path, n, tt.value, tt.offset)
+		}
+	}
+	for _, path := range []string{"30", "29.02", "62.05.01", "99"} {
+		if n := p.Find(path); n != nil {
+			t.Errorf("Find(%s) = %+v, want nil", path, n)
+		}
+	}
+
+	// Offsets count bytes, lengths count characters
+	p, err = Parse("000201" + "64290002TH0108ร้านกาแฟ0207กรุงเทพ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := p.Find("64.02"); n == nil || n.Value != "กรุงเทพ" || n.Offset != 44 {
+		t.Errorf("Find(64.02) = %+v, want กรุงเทพ at offset 44", n)
+	}
+	if n := p.Get("62"); n != nil {
+		t.Errorf("Get(62) = %+v, want nil", n)
+	}
+}
+
+func TestPayloadEditAndSign(t *testing.T) {
+	p, err := Parse(decoderPayloads[1].payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Sign()
+	if got := p.String(); got != decoderPayloads[1].payload {
+		t.Errorf("Sign() of a signed payload = %s, want %s", got, decoderPayloads[1].payload)
+	}
+
+	p.Find("62.05").Value = "INV-0001"
+	p.Sign()
+	const edited = "00020101021229370016A000000677010111011300668123456785204581453037645406100.505802TH5904SHOP6007BANGKOK62120508INV-0001"
+	if got := p.String(); got != withCRC(edited) {
+		t.Errorf("edited payload = %s, want %s", got, withCRC(edited))
+	}
+
+	p, _ = Parse("000201" + "6304FFFF" + "5802TH")
+	p.Sign()
+	if got := p.String(); got != withCRC("0002015802TH") {
+		t.Errorf("Sign() = %s, want the CRC moved to the end: %s", got, withCRC("0002015802TH"))
+	}
+}
@@ -0,0 +1,53 @@
+package qr
+
+// PointOfInitiation is the Point of Initiation Method (01)
+type PointOfInitiation string
+
+const (
+	POIStatic  PointOfInitiation = "11" // the same QR is shown for more than one transaction
+	POIDynamic PointOfInitiation = "12" // a new QR is shown for each transaction
+)
+
+// Valid reports whether p is static or dynamic
+func (p PointOfInitiation) Valid() bool {
+	return p == POIStatic || p == POIDynamic
+}
+
+// Reusable reports whether a QR with p can be paid more than once
+func (p PointOfInitiation) Reusable() bool {
+	return p != POIDynamic
+}
+
+func (p PointOfInitiation) String() string {
+	switch p {
+	case POIStatic:
+		return "static"
+	case POIDynamic:
+		return "dynamic"
+	}
+	return string(p)
+}
+
+// InitiationMethod returns the Point of Initiation Method of q, when it is not set:
+// static without an amount and dynamic with one.
+func (q *QR) InitiationMethod() PointOfInitiation {
+	if q.PointOfInitiationMethod != "" {
+		return q.PointOfInitiationMethod
+	}
+	if q.Transaction.Amount.IsSet() {
+		return POIDynamic
+	}
+	return POIStatic
+}
+
+// SingleUse reports whether q must be paid only once, reconciliation should
+// match its reference against a single transaction.
+func (q *QR) SingleUse() bool {
+	return !q.InitiationMethod().Reusable()
+}
+
+// hasReference reports whether q carries a reference that identifies the transaction
+func hasReference(q *QR) bool {
+	return q.AdditionalData.ReferenceID != "" || q.AdditionalData.BillNumber != "" ||
+		q.Merchant.ID.PromptPayBillPayment.Reference1 != "" || q.Merchant.ID.API.TransactionRef != ""
+}
@@ -0,0 +1,298 @@
+package qr
+
+import (
+	"strings"
+	"sync"
+)
+
+// Profile is a regional specification built on EMVCo merchant-presented QR,
+// such as Thai QR Payment or Singapore SGQR.
+type Profile interface {
+	Name() string
+	Country() string      // Country Code (58)
+	Currencies() []string // accepted Transaction Currencies (53)
+	MandatoryTags() []string
+	GUIDs() []string // Merchant Account Information GUIDs (sub-tag 00 of 26-51)
+	// Detect reports whether p looks like a payload of this profile
+	Detect(p *Payload) bool
+	// Check returns a *ParseError for the first rule p breaks
+	Check(p *Payload) error
+}
+
+// profileRule returns a *ParseError when p breaks the rule
+type profileRule func(p *Payload) error
+
+// profile is the Profile implementation of the built-in profiles
+type profile struct {
+	name       string
+	country    string
+	currencies []string
+	mandatory  []string
+	guids      []string
+	rules      []profileRule
+}
+
+func (pr *profile) Name() string            { return pr.name }
+func (pr *profile) Country() string         { return pr.country }
+func (pr *profile) Currencies() []string    { return pr.currencies }
+func (pr *profile) MandatoryTags() []string { return pr.mandatory }
+func (pr *profile) GUIDs() []string         { return pr.guids }
+
+func (pr *profile) Detect(p *Payload) bool {
+	if n := p.Get("58"); n != nil && n.Value == pr.country {
+		return true
+	}
+	return pr.hasGUID(p)
+}
+
+func (pr *profile) Check(p *Payload) error {
+	return pr.check(p, true, true)
+}
+
+// check is Check, the country and the currencies of the profile are only
+// checked when asked, the Decoder leaves them to its own policy
+func (pr *profile) check(p *Payload, country, currency bool) error {
+	if n := p.Get("58"); country && (n == nil || n.Value != pr.country) {
+		return p.errorAt("58", ErrUnsupportedCountry, pr.country, valueOf(n))
+	}
+	n := p.Get("53")
+	found := false
+	for _, c := range pr.currencies {
+		found = found || (n != nil && n.Value == c)
+	}
+	if currency && !found {
+		return p.errorAt("53", ErrUnsupportedCurrency, strings.Join(pr.currencies, ", "), valueOf(n))
+	}
+	for _, id := range pr.mandatory {
+		if n := p.Get(id); n == nil || n.Value == "" {
+			return &ParseError{Offset: -1, Path: id, Err: ErrMissingTag}
+		}
+	}
+	if !hasAccountTag(p) {
+		return &ParseError{Offset: -1, Path: "02-51", Expected: "Merchant Account Information", Err: ErrMissingTag}
+	}
+	for _, rule := range pr.rules {
+		if err := rule(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (pr *profile) hasGUID(p *Payload) bool {
+	for _, guid := range merchantAccountGUIDs(p) {
+		for _, g := range pr.guids {
+			if strings.EqualFold(guid, g) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasAccountTag reports whether p has at least one Merchant Account Information (02-51)
+func hasAccountTag(p *Payload) bool {
+	for _, n := range p.Nodes {
+		if n.ID >= "02" && n.ID <= "51" && n.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// merchantAccountGUIDs returns the sub-tag 00 of every Merchant Account Information template
+func merchantAccountGUIDs(p *Payload) []string {
+	var guids []string
+	for _, n := range p.Nodes {
+		if n.ID < "26" || n.ID > "51" {
+			continue
+		}
+		if g := n.Get("00"); g != nil {
+			guids = append(guids, g.Value)
+		}
+	}
+	return guids
+}
+
+// errorAt returns a ParseError at the offset of the node at path, -1 when it is missing
+func (p *Payload) errorAt(path string, err error, expected, actual string) *ParseError {
+	pe := tagError(path, err, expected, actual)
+	if n := p.Find(path); n != nil {
+		pe.Offset = n.Offset
+	}
+	return pe
+}
+
+func valueOf(n *Node) string {
+	if n == nil {
+		return ""
+	}
+	return n.Value
+}
+
+// requireGUID checks that the template id, when present, has the given GUID
+func requireGUID(id, guid string) profileRule {
+	return func(p *Payload) error {
+		n := p.Get(id)
+		if n == nil {
+			return nil
+		}
+		if g := n.Get("00"); g == nil || g.Value != guid {
+			return p.errorAt(id+".00", ErrBadValue, guid, valueOf(g))
+		}
+		return nil
+	}
+}
+
+// requireSubTag checks that the template with guid has a non-empty sub-tag
+// accepted by valid, valid may be nil
+func requireSubTag(guid, sub string, expected string, valid func(string) bool) profileRule {
+	return func(p *Payload) error {
+		for _, n := range p.Nodes {
+			if g := n.Get("00"); g == nil || !strings.EqualFold(g.Value, guid) {
+				continue
+			}
+			path := n.ID + "." + sub
+			s := n.Get(sub)
+			if s == nil || s.Value == "" {
+				return &ParseError{Offset: n.Offset, Path: path, Expected: expected, Err: ErrMissingTag}
+			}
+			if valid != nil && !valid(s.Value) {
+				return p.errorAt(path, ErrBadValue, expected, s.Value)
+			}
+		}
+		return nil
+	}
+}
+
+func oneOf(values ...string) func(string) bool {
+	return func(s string) bool {
+		for _, v := range values {
+			if s == v {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Built-in profiles
+var (
+	// ThaiQR is Thai QR Payment (PromptPay), the rules of DecodeQRVisa
+	ThaiQR Profile = &profile{
+		name:       "Thai QR Payment",
+		country:    "TH",
+		currencies: []string{"764"},
+		mandatory:  []string{"53", "58", "63"},
+		guids:      []string{GUIDPromptPay, GUIDBillPayment, GUIDPromptPayAPI, "A000000677010114"},
+		rules: []profileRule{
+			requireGUID("29", GUIDPromptPay),
+			requireGUID("30", GUIDBillPayment),
+		},
+	}
+	// SGQR is Singapore SGQR, including PayNow
+	SGQR Profile = &profile{
+		name:       "SGQR",
+		country:    "SG",
+		currencies: []string{"702"},
+		mandatory:  []string{"00", "52", "53", "58", "59", "60", "63"},
+		guids:      []string{"SG.PAYNOW", "SG.SGQR", "SG.COM.NETS", "COM.GRAB", "SG.AIRTEL", "SG.COM.DASH.WWW"},
+		rules: []profileRule{
+			requireSubTag("SG.PAYNOW", "01", "proxy type 0 (mobile) or 2 (UEN)", oneOf("0", "2")),
+			requireSubTag("SG.PAYNOW", "02", "proxy value", nil),
+			requireSubTag("SG.SGQR", "01", "12 characters SGQR ID", func(s string) bool { return len(s) == 12 }),
+		},
+	}
+	// DuitNow is Malaysia DuitNow QR
+	DuitNow Profile = &profile{
+		name:       "DuitNow QR",
+		country:    "MY",
+		currencies: []string{"458"},
+		mandatory:  []string{"00", "52", "53", "58", "59", "60", "63"},
+		guids:      []string{"A000000615000101"},
+		rules: []profileRule{
+			requireSubTag("A000000615000101", "01", "acquirer ID", nil),
+			requireSubTag("A000000615000101", "02", "merchant ID", nil),
+		},
+	}
+	// QRIS is Indonesia Quick Response Code Indonesian Standard
+	QRIS Profile = &profile{
+		name:       "QRIS",
+		country:    "ID",
+		currencies: []string{"360"},
+		mandatory:  []string{"00", "51", "52", "53", "58", "59", "60", "63"},
+		guids:      []string{"ID.CO.QRIS.WWW"},
+		rules: []profileRule{
+			requireGUID("51", "ID.CO.QRIS.WWW"),
+			requireSubTag("ID.CO.QRIS.WWW", "02", "National Merchant ID starting with ID", func(s string) bool { return strings.HasPrefix(s, "ID") }),
+			requireSubTag("ID.CO.QRIS.WWW", "03", "merchant criteria UMI, UKE, UME, UBE or URE", oneOf("UMI", "UKE", "UME", "UBE", "URE")),
+		},
+	}
+	// KHQR is Cambodia KHQR on Bakong, in riel or US dollar
+	KHQR Profile = &profile{
+		name:       "KHQR",
+		country:    "KH",
+		currencies: []string{"116", "840"},
+		mandatory:  []string{"00", "52", "53", "58", "59", "60", "63"},
+		rules:      []profileRule{requireBakongAccount},
+	}
+	// VietQR is Vietnam VietQR on NAPAS
+	VietQR Profile = &profile{
+		name:       "VietQR",
+		country:    "VN",
+		currencies: []string{"704"},
+		mandatory:  []string{"00", "38", "53", "58", "63"},
+		guids:      []string{"A000000727"},
+		rules: []profileRule{
+			requireGUID("38", "A000000727"),
+			requireSubTag("A000000727", "01", "beneficiary organization", nil),
+			requireSubTag("A000000727", "02", "service code QRIBFTTA, QRIBFTTC or QRPUSH", oneOf("QRIBFTTA", "QRIBFTTC", "QRPUSH")),
+		},
+	}
+)
+
+// requireBakongAccount checks that a KHQR has an individual (29) or merchant (30)
+// account with a Bakong account ID such as "name@bank"
+func requireBakongAccount(p *Payload) error {
+	for _, id := range []string{"29", "30"} {
+		n := p.Get(id)
+		if n == nil {
+			continue
+		}
+		a := n.Get("00")
+		if a == nil || !strings.Contains(a.Value, "@") {
+			return p.errorAt(id+".00", ErrBadValue, "Bakong account ID", valueOf(a))
+		}
+		return nil
+	}
+	return &ParseError{Offset: -1, Path: "29", Expected: "Bakong individual (29) or merchant (30) account", Err: ErrMissingTag}
+}
+
+var (
+	profileMu sync.RWMutex
+	profiles  = []Profile{ThaiQR, SGQR, DuitNow, QRIS, KHQR, VietQR}
+)
+
+// RegisterProfile adds p to the profiles tried by DetectProfile, after the built-in ones
+func RegisterProfile(p Profile) {
+	profileMu.Lock()
+	defer profileMu.Unlock()
+	profiles = append(profiles, p)
+}
+
+// Profiles returns the registered profiles in detection order
+func Profiles() []Profile {
+	profileMu.RLock()
+	defer profileMu.RUnlock()
+	return append([]Profile(nil), profiles...)
+}
+
+// DetectProfile returns the first registered profile that detects p, nil when none does
+func DetectProfile(p *Payload) Profile {
+	for _, pr := range Profiles() {
+		if pr.Detect(p) {
+			return pr
+		}
+	}
+	return nil
+}
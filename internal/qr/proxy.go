@@ -0,0 +1,158 @@
+package qr
+
+import (
+	"strings"
+)
+
+// ProxyType is the kind of PromptPay proxy found in tag 29
+type ProxyType int
+
+const (
+	ProxyNone ProxyType = iota
+	ProxyMobile
+	ProxyNationalID
+	ProxyEWallet
+	ProxyBankAccount
+	ProxyNationalEWallet
+)
+
+func (p ProxyType) String() string {
+	switch p {
+	case ProxyMobile:
+		return "mobile number"
+	case ProxyNationalID:
+		return "national ID / tax ID"
+	case ProxyEWallet:
+		return "e-wallet ID"
+	case ProxyBankAccount:
+		return "bank account"
+	case ProxyNationalEWallet:
+		return "national e-wallet ID"
+	}
+	return "none"
+}
+
+var proxyCleaner = strings.NewReplacer("-", "", " ", "", "+", "")
+
+// NormalizeMobile converts a Thai mobile number such as "081-234-5678",
+// "+66812345678" or "66812345678" to the 13 digits PromptPay form "0066812345678"
+func NormalizeMobile(s string) (string, error) {
+	n := proxyCleaner.Replace(s)
+	if !IsDigits(n) {
+		return "", tagError("29.01", ErrBadValue, "Thai mobile number", s)
+	}
+	switch {
+	case len(n) == 10 && n[0] == '0':
+		n = "0066" + n[1:]
+	case len(n) == 11 && strings.HasPrefix(n, "66"):
+		n = "00" + n
+	}
+	if len(n) != 13 || !strings.HasPrefix(n, "0066") || n[4] == '0' {
+		return "", tagError("29.01", ErrBadValue, "Thai mobile number", s)
+	}
+	return n, nil
+}
+
+// NormalizeNationalID removes dashes and spaces from a 13 digits national ID
+// or tax ID and checks its mod-11 check digit
+func NormalizeNationalID(s string) (string, error) {
+	n := proxyCleaner.Replace(s)
+	if !ValidThaiID(n) {
+		return "", tagError("29.02", ErrBadValue, "13 digits national ID with a valid check digit", s)
+	}
+	return n, nil
+}
+
+// NormalizeEWallet removes dashes and spaces from a 15 digits e-wallet ID
+func NormalizeEWallet(s string) (string, error) {
+	n := proxyCleaner.Replace(s)
+	if len(n) != 15 || !IsDigits(n) {
+		return "", tagError("29.03", ErrBadValue, "15 digits e-wallet ID", s)
+	}
+	return n, nil
+}
+
+// ValidThaiID reports whether s is a 13 digits national ID or tax ID with a valid mod-11 check digit
+func ValidThaiID(s string) bool {
+	if len(s) != 13 || !IsDigits(s) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 12; i++ {
+		sum += int(s[i]-'0') * (13 - i)
+	}
+	return (11-sum%11)%10 == int(s[12]-'0')
+}
+
+// DetectProxy guesses the kind of proxy from its digits and returns it normalized
+func DetectProxy(s string) (ProxyType, string, error) {
+	n := proxyCleaner.Replace(s)
+	switch {
+	case len(n) == 13 && !strings.HasPrefix(n, "0066"):
+		v, err := NormalizeNationalID(s)
+		return ProxyNationalID, v, err
+	case len(n) == 15:
+		v, err := NormalizeEWallet(s)
+		return ProxyEWallet, v, err
+	default:
+		v, err := NormalizeMobile(s)
+		return ProxyMobile, v, err
+	}
+}
+
+// FormatMobile returns a PromptPay mobile number as "081-234-5678"
+func FormatMobile(s string) string {
+	if len(s) == 13 && strings.HasPrefix(s, "0066") {
+		s = "0" + s[4:]
+	}
+	if len(s) != 10 {
+		return s
+	}
+	return s[:3] + "-" + s[3:6] + "-" + s[6:]
+}
+
+// FormatNationalID returns a national ID as "1-2345-67890-12-3"
+func FormatNationalID(s string) string {
+	if len(s) != 13 {
+		return s
+	}
+	return s[:1] + "-" + s[1:5] + "-" + s[5:10] + "-" + s[10:12] + "-" + s[12:]
+}
+
+// Proxy returns the kind of proxy set in p and its human-friendly display form
+func (p QRMerchantIDPromptPay) Proxy() (ProxyType, string) {
+	switch {
+	case p.MobileNumber != "":
+		return ProxyMobile, FormatMobile(p.MobileNumber)
+	case p.NationalID != "":
+		return ProxyNationalID, FormatNationalID(p.NationalID)
+	case p.EWalletID != "":
+		return ProxyEWallet, p.EWalletID
+	case p.BankAccount != "":
+		return ProxyBankAccount, p.BankAccount
+	case p.NationalEWalletID != "":
+		return ProxyNationalEWallet, p.NationalEWalletID
+	}
+	return ProxyNone, ""
+}
+
+// normalizePromptPay normalizes and checks the proxies of p before encoding
+func normalizePromptPay(p QRMerchantIDPromptPay) (QRMerchantIDPromptPay, error) {
+	var err error
+	if p.MobileNumber != "" {
+		if p.MobileNumber, err = NormalizeMobile(p.MobileNumber); err != nil {
+			return p, err
+		}
+	}
+	if p.NationalID != "" {
+		if p.NationalID, err = NormalizeNationalID(p.NationalID); err != nil {
+			return p, err
+		}
+	}
+	if p.EWalletID != "" {
+		if p.EWalletID, err = NormalizeEWallet(p.EWalletID); err != nil {
+			return p, err
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,132 @@
+package qr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidThaiID(t *testing.T) {
+	tests := []struct {
+		id    string
+		valid bool
+	}{
+		{"1101400188170", true},
+		{"0994000165501", true}, // tax ID of a company
+		{"3100600445597", true},
+		{"1101400188171", false},
+		{"0994000165502", false},
+		{"110140018817", false},
+		{"11014001881700", false},
+		{"110140018817A", false},
+		{"1-1014-00188-17-0", false}, // dashes are removed by NormalizeNationalID
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ValidThaiID(tt.id); got != tt.valid {
+			t.Errorf("ValidThaiID(%q) = %v, want %v", tt.id, got, tt.valid)
+		}
+	}
+}
+
+func TestNormalizeProxy(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind ProxyType
+		want string
+		path string // tag of the error, "" for none
+	}{
+		{"0812345678", ProxyMobile, "0066812345678", ""},
+		{"081-234-5678", ProxyMobile, "0066812345678", ""},
+		{"081 234 5678", ProxyMobile, "0066812345678", ""},
+		{"+66812345678", ProxyMobile, "0066812345678", ""},
+		{"66812345678", ProxyMobile, "0066812345678", ""},
+		{"0066812345678", ProxyMobile, "0066812345678", ""},
+		{"0066012345678", ProxyMobile, "", "29.01"},
+		{"081234567", ProxyMobile, "", "29.01"},
+		{"08123456789", ProxyMobile, "", "29.01"},
+		{"081-234-567A", ProxyMobile, "", "29.01"},
+		{"1-1014-00188-17-0", ProxyNationalID, "1101400188170", ""},
+		{"1 1014 00188 17 0", ProxyNationalID, "1101400188170", ""},
+		{"0994000165501", ProxyNationalID, "0994000165501", ""},
+		{"1-1014-00188-17-1", ProxyNationalID, "", "29.02"},
+		{"004999000288505", ProxyEWallet, "004999000288505", ""},
+		{"004-999-000-288-505", ProxyEWallet, "004999000288505", ""},
+		{"00499900028850A", ProxyEWallet, "", "29.03"},
+	}
+	for _, tt := range tests {
+		kind, got, err := DetectProxy(tt.in)
+		if kind != tt.kind || got != tt.want {
+			t.Errorf("DetectProxy(%q) = %v, %q, want %v, %q", tt.in, kind, got, tt.kind, tt.want)
+		}
+		var pe *ParseError
+		switch {
+		case tt.path == "" && err != nil:
+			t.Errorf("DetectProxy(%q) error = %v", tt.in, err)
+		case tt.path != "" && (!errors.As(err, &pe) || pe.Path != tt.path || !errors.Is(err, ErrBadValue) || pe.Actual != tt.in):
+			t.Errorf("DetectProxy(%q) error = %v, want a bad value at %s", tt.in, err, tt.path)
+		}
+	}
+}
+
+func TestEncodeNormalizesPromptPay(t *testing.T) {
+	tests := []struct {
+		proxy QRMerchantIDPromptPay
+		want  string // value of tag 29
+		path  string // tag of the error, "" for none
+	}{
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, MobileNumber: "081-234-5678"}, "0016A00000067701011101130066812345678", ""},
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, NationalID: "1-1014-00188-17-0"}, "0016A00000067701011102131101400188170", ""},
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, EWalletID: "004 999 000 288 505"}, "0016A0000006770101110315004999000288505", ""},
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, MobileNumber: "12345"}, "", "29.01"},
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, NationalID: "1101400188171"}, "", "29.02"},
+		{QRMerchantIDPromptPay{AID: GUIDPromptPay, EWalletID: "12345"}, "", "29.03"},
+	}
+	for _, tt := range tests {
+		q := validQR()
+		q.Merchant.ID.PromptPay = tt.proxy
+		m, err := ConvertQRToMap(q)
+		if tt.path != "" {
+			var pe *ParseError
+			if !errors.As(err, &pe) || pe.Path != tt.path {
+				t.Errorf("ConvertQRToMap(%+v) error = %v, want an error at %s", tt.proxy, err, tt.path)
+			}
+			found := false
+			for _, issue := range Validate(q).Errors() {
+				found = found || issue.Tag == tt.path && issue.Rule == RuleProxy
+			}
+			if !found {
+				t.Errorf("Validate(%+v) has no %s error at %s", tt.proxy, RuleProxy, tt.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ConvertQRToMap(%+v) error = %v", tt.proxy, err)
+			continue
+		}
+		if m["29"] != tt.want {
+			t.Errorf("ConvertQRToMap(%+v) tag 29 = %s, want %s", tt.proxy, m["29"], tt.want)
+		}
+		if q.Merchant.ID.PromptPay != tt.proxy {
+			t.Errorf("ConvertQRToMap changed the QR to %+v", q.Merchant.ID.PromptPay)
+		}
+	}
+}
+
+func TestProxyDisplay(t *testing.T) {
+	tests := []struct {
+		p    QRMerchantIDPromptPay
+		kind ProxyType
+		want string
+	}{
+		{QRMerchantIDPromptPay{MobileNumber: "0066812345678"}, ProxyMobile, "081-234-5678"},
+		{QRMerchantIDPromptPay{NationalID: "1101400188170"}, ProxyNationalID, "1-1014-00188-17-0"},
+		{QRMerchantIDPromptPay{EWalletID: "004999000288505"}, ProxyEWallet, "004999000288505"},
+		{QRMerchantIDPromptPay{BankAccount: "0141234567890"}, ProxyBankAccount, "0141234567890"},
+		{QRMerchantIDPromptPay{}, ProxyNone, ""},
+	}
+	for _, tt := range tests {
+		if kind, got := tt.p.Proxy(); kind != tt.kind || got != tt.want {
+			t.Errorf("%+v.Proxy() = %v, %q, want %v, %q", tt.p, kind, got, tt.kind, tt.want)
+		}
+	}
+}
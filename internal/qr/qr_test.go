@@ -0,0 +1,54 @@
+package qr
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConvertMapToQR(t *testing.T) {
+	canonical := decoderPayloads[1].payload
+	tests := []struct {
+		name string
+		edit func(m map[string]string)
+		err  error
+	}{
+		{"canonical order", func(m map[string]string) {}, nil},
+		{"lower case CRC", func(m map[string]string) { m["63"] = "ff19" }, nil},
+		{"wrong CRC", func(m map[string]string) { m["63"] = "0000" }, ErrCRCMismatch},
+		{"edited tag", func(m map[string]string) { m["59"] = "SHOP2" }, ErrCRCMismatch},
+		{"missing CRC", func(m map[string]string) { delete(m, "63") }, ErrMissingTag},
+	}
+	for _, tt := range tests {
+		m, err := ConvertStringToMap(canonical)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.edit(m)
+		q, err := ConvertMapToQR(m)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err == nil && q.Transaction.Amount.String() != "100.50" {
+			t.Errorf("%s: decoded amount %q", tt.name, q.Transaction.Amount)
+		}
+		if pe, ok := err.(*ParseError); ok && pe.Path != "63" {
+			t.Errorf("%s: error at tag %s, want 63", tt.name, pe.Path)
+		}
+	}
+}
+
+func TestConvertMapToQRNonCanonical(t *testing.T) {
+	// Tag 51 follows 62 in the sample, its CRC is not the one of the tags in ascending order
+	sample := decoderPayloads[0].payload
+	m, err := ConvertStringToMap(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ConvertMapToQR(m); !errors.Is(err, ErrCRCMismatch) {
+		t.Errorf("got %v, want ErrCRCMismatch", err)
+	}
+	if _, err := DecodeQRVisa(sample); err != nil {
+		t.Errorf("original string: %v", err)
+	}
+}
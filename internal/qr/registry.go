@@ -0,0 +1,98 @@
+package qr
+
+import (
+	"strings"
+)
+
+// Currency is an ISO 4217 currency
+type Currency struct {
+	Alpha    string // e.g. "THB"
+	Numeric  string // e.g. "764", used in tag 53
+	Exponent int    // number of decimals of the minor unit
+	Name     string
+}
+
+// Country is an ISO 3166-1 country
+type Country struct {
+	Alpha2  string // e.g. "TH", used in tag 58
+	Alpha3  string
+	Numeric string
+	Name    string
+}
+
+var (
+	currencyIndex = map[string]Currency{}
+	countryIndex  = map[string]Country{}
+)
+
+func init() {
+	for _, c := range currencies {
+		currencyIndex[c.Alpha] = c
+		currencyIndex[c.Numeric] = c
+	}
+	for _, c := range countries {
+		countryIndex[c.Alpha2] = c
+		countryIndex[c.Alpha3] = c
+		countryIndex[c.Numeric] = c
+	}
+}
+
+// LookupCurrency returns the currency with the alpha or numeric code
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := currencyIndex[strings.ToUpper(code)]
+	return c, ok
+}
+
+// LookupCountry returns the country with the alpha-2, alpha-3 or numeric code
+func LookupCountry(code string) (Country, bool) {
+	c, ok := countryIndex[strings.ToUpper(code)]
+	return c, ok
+}
+
+// Currencies returns every registered currency ordered by numeric code
+func Currencies() []Currency {
+	return append([]Currency(nil), currencies...)
+}
+
+// Countries returns every registered country ordered by numeric code
+func Countries() []Country {
+	return append([]Country(nil), countries...)
+}
+
+// CurrencyExponent returns the number of decimals of the currency, 2 when it is unknown
+func CurrencyExponent(currency string) int {
+	if c, ok := LookupCurrency(currency); ok {
+		return c.Exponent
+	}
+	return 2
+}
+
+// allowedCountry reports whether the tag 58 value code is a known country listed in allowed,
+// a nil allowed accepts any known country
+func allowedCountry(code string, allowed []string) bool {
+	c, ok := LookupCountry(code)
+	if !ok || c.Alpha2 != code {
+		return false
+	}
+	for _, a := range allowed {
+		if ac, ok := LookupCountry(a); ok && ac == c {
+			return true
+		}
+	}
+	return allowed == nil
+}
+
+// allowedCurrency reports whether the tag 53 value code is a known currency listed in allowed,
+// a nil allowed accepts any known currency
+func allowedCurrency(code string, allowed []string) bool {
+	c, ok := LookupCurrency(code)
+	if !ok || c.Numeric != code {
+		return false
+	}
+	for _, a := range allowed {
+		if ac, ok := LookupCurrency(a); ok && ac == c {
+			return true
+		}
+	}
+	return allowed == nil
+}
@@ -0,0 +1,426 @@
+package qr
+
+// currencies is the ISO 4217 list of currencies with their minor unit exponent
+var currencies = []Currency{
+	{"ALL", "008", 2, "Lek"},
+	{"DZD", "012", 2, "Algerian Dinar"},
+	{"ARS", "032", 2, "Argentine Peso"},
+	{"AUD", "036", 2, "Australian Dollar"},
+	{"BSD", "044", 2, "Bahamian Dollar"},
+	{"BHD", "048", 3, "Bahraini Dinar"},
+	{"BDT", "050", 2, "Taka"},
+	{"AMD", "051", 2, "Armenian Dram"},
+	{"BBD", "052", 2, "Barbados Dollar"},
+	{"BMD", "060", 2, "Bermudian Dollar"},
+	{"BTN", "064", 2, "Ngultrum"},
+	{"BOB", "068", 2, "Boliviano"},
+	{"BWP", "072", 2, "Pula"},
+	{"BZD", "084", 2, "Belize Dollar"},
+	{"SBD", "090", 2, "Solomon Islands Dollar"},
+	{"BND", "096", 2, "Brunei Dollar"},
+	{"MMK", "104", 2, "Kyat"},
+	{"BIF", "108", 0, "Burundi Franc"},
+	{"KHR", "116", 2, "Riel"},
+	{"CAD", "124", 2, "Canadian Dollar"},
+	{"CVE", "132", 2, "Cabo Verde Escudo"},
+	{"KYD", "136", 2, "Cayman Islands Dollar"},
+	{"LKR", "144", 2, "Sri Lanka Rupee"},
+	{"CLP", "152", 0, "Chilean Peso"},
+	{"CNY", "156", 2, "Yuan Renminbi"},
+	{"COP", "170", 2, "Colombian Peso"},
+	{"KMF", "174", 0, "Comorian Franc"},
+	{"CRC", "188", 2, "Costa Rican Colon"},
+	{"HRK", "191", 2, "Kuna"},
+	{"CUP", "192", 2, "Cuban Peso"},
+	{"CZK", "203", 2, "Czech Koruna"},
+	{"DKK", "208", 2, "Danish Krone"},
+	{"DOP", "214", 2, "Dominican Peso"},
+	{"SVC", "222", 2, "El Salvador Colon"},
+	{"ETB", "230", 2, "Ethiopian Birr"},
+	{"ERN", "232", 2, "Nakfa"},
+	{"FKP", "238", 2, "Falkland Islands Pound"},
+	{"FJD", "242", 2, "Fiji Dollar"},
+	{"DJF", "262", 0, "Djibouti Franc"},
+	{"GMD", "270", 2, "Dalasi"},
+	{"GIP", "292", 2, "Gibraltar Pound"},
+	{"GTQ", "320", 2, "Quetzal"},
+	{"GNF", "324", 0, "Guinean Franc"},
+	{"GYD", "328", 2, "Guyana Dollar"},
+	{"HTG", "332", 2, "Gourde"},
+	{"HNL", "340", 2, "Lempira"},
+	{"HKD", "344", 2, "Hong Kong Dollar"},
+	{"HUF", "348", 2, "Forint"},
+	{"ISK", "352", 0, "Iceland Krona"},
+	{"INR", "356", 2, "Indian Rupee"},
+	{"IDR", "360", 2, "Rupiah"},
+	{"IRR", "364", 2, "Iranian Rial"},
+	{"IQD", "368", 3, "Iraqi Dinar"},
+	{"ILS", "376", 2, "New Israeli Sheqel"},
+	{"JMD", "388", 2, "Jamaican Dollar"},
+	{"JPY", "392", 0, "Yen"},
+	{"KZT", "398", 2, "Tenge"},
+	{"JOD", "400", 3, "Jordanian Dinar"},
+	{"KES", "404", 2, "Kenyan Shilling"},
+	{"KPW", "408", 2, "North Korean Won"},
+	{"KRW", "410", 0, "Won"},
+	{"KWD", "414", 3, "Kuwaiti Dinar"},
+	{"KGS", "417", 2, "Som"},
+	{"LAK", "418", 2, "Lao Kip"},
+	{"LBP", "422", 2, "Lebanese Pound"},
+	{"LSL", "426", 2, "Loti"},
+	{"LRD", "430", 2, "Liberian Dollar"},
+	{"LYD", "434", 3, "Libyan Dinar"},
+	{"MOP", "446", 2, "Pataca"},
+	{"MWK", "454", 2, "Malawi Kwacha"},
+	{"MYR", "458", 2, "Malaysian Ringgit"},
+	{"MVR", "462", 2, "Rufiyaa"},
+	{"MUR", "480", 2, "Mauritius Rupee"},
+	{"MXN", "484", 2, "Mexican Peso"},
+	{"MNT", "496", 2, "Tugrik"},
+	{"MDL", "498", 2, "Moldovan Leu"},
+	{"MAD", "504", 2, "Moroccan Dirham"},
+	{"OMR", "512", 3, "Rial Omani"},
+	{"NAD", "516", 2, "Namibia Dollar"},
+	{"NPR", "524", 2, "Nepalese Rupee"},
+	{"ANG", "532", 2, "Netherlands Antillean Guilder"},
+	{"AWG", "533", 2, "Aruban Florin"},
+	{"VUV", "548", 0, "Vatu"},
+	{"NZD", "554", 2, "New Zealand Dollar"},
+	{"NIO", "558", 2, "Cordoba Oro"},
+	{"NGN", "566", 2, "Naira"},
+	{"NOK", "578", 2, "Norwegian Krone"},
+	{"PKR", "586", 2, "Pakistan Rupee"},
+	{"PAB", "590", 2, "Balboa"},
+	{"PGK", "598", 2, "Kina"},
+	{"PYG", "600", 0, "Guarani"},
+	{"PEN", "604", 2, "Sol"},
+	{"PHP", "608", 2, "Philippine Peso"},
+	{"QAR", "634", 2, "Qatari Rial"},
+	{"RUB", "643", 2, "Russian Ruble"},
+	{"RWF", "646", 0, "Rwanda Franc"},
+	{"SHP", "654", 2, "Saint Helena Pound"},
+	{"SAR", "682", 2, "Saudi Riyal"},
+	{"SCR", "690", 2, "Seychelles Rupee"},
+	{"SLL", "694", 2, "Leone"},
+	{"SGD", "702", 2, "Singapore Dollar"},
+	{"VND", "704", 0, "Dong"},
+	{"SOS", "706", 2, "Somali Shilling"},
+	{"ZAR", "710", 2, "Rand"},
+	{"SSP", "728", 2, "South Sudanese Pound"},
+	{"SZL", "748", 2, "Lilangeni"},
+	{"SEK", "752", 2, "Swedish Krona"},
+	{"CHF", "756", 2, "Swiss Franc"},
+	{"SYP", "760", 2, "Syrian Pound"},
+	{"THB", "764", 2, "Baht"},
+	{"TOP", "776", 2, "Pa’anga"},
+	{"TTD", "780", 2, "Trinidad and Tobago Dollar"},
+	{"AED", "784", 2, "UAE Dirham"},
+	{"TND", "788", 3, "Tunisian Dinar"},
+	{"UGX", "800", 0, "Uganda Shilling"},
+	{"MKD", "807", 2, "Denar"},
+	{"EGP", "818", 2, "Egyptian Pound"},
+	{"GBP", "826", 2, "Pound Sterling"},
+	{"TZS", "834", 2, "Tanzanian Shilling"},
+	{"USD", "840", 2, "US Dollar"},
+	{"UYU", "858", 2, "Peso Uruguayo"},
+	{"UZS", "860", 2, "Uzbekistan Sum"},
+	{"WST", "882", 2, "Tala"},
+	{"YER", "886", 2, "Yemeni Rial"},
+	{"TWD", "901", 2, "New Taiwan Dollar"},
+	{"SLE", "925", 2, "Leone"},
+	{"VED", "926", 2, "Bolívar Soberano"},
+	{"UYW", "927", 4, "Unidad Previsional"},
+	{"VES", "928", 2, "Bolívar Soberano"},
+	{"MRU", "929", 2, "Ouguiya"},
+	{"STN", "930", 2, "Dobra"},
+	{"CUC", "931", 2, "Peso Convertible"},
+	{"ZWL", "932", 2, "Zimbabwe Dollar"},
+	{"BYN", "933", 2, "Belarusian Ruble"},
+	{"TMT", "934", 2, "Turkmenistan New Manat"},
+	{"GHS", "936", 2, "Ghana Cedi"},
+	{"SDG", "938", 2, "Sudanese Pound"},
+	{"UYI", "940", 0, "Uruguay Peso en Unidades Indexadas (UI)"},
+	{"RSD", "941", 2, "Serbian Dinar"},
+	{"MZN", "943", 2, "Mozambique Metical"},
+	{"AZN", "944", 2, "Azerbaijan Manat"},
+	{"RON", "946", 2, "Romanian Leu"},
+	{"CHE", "947", 2, "WIR Euro"},
+	{"CHW", "948", 2, "WIR Franc"},
+	{"TRY", "949", 2, "Turkish Lira"},
+	{"XAF", "950", 0, "CFA Franc BEAC"},
+	{"XCD", "951", 2, "East Caribbean Dollar"},
+	{"XOF", "952", 0, "CFA Franc BCEAO"},
+	{"XPF", "953", 0, "CFP Franc"},
+	{"ZMW", "967", 2, "Zambian Kwacha"},
+	{"SRD", "968", 2, "Surinam Dollar"},
+	{"MGA", "969", 2, "Malagasy Ariary"},
+	{"COU", "970", 2, "Unidad de Valor Real"},
+	{"AFN", "971", 2, "Afghani"},
+	{"TJS", "972", 2, "Somoni"},
+	{"AOA", "973", 2, "Kwanza"},
+	{"BGN", "975", 2, "Bulgarian Lev"},
+	{"CDF", "976", 2, "Congolese Franc"},
+	{"BAM", "977", 2, "Convertible Mark"},
+	{"EUR", "978", 2, "Euro"},
+	{"MXV", "979", 2, "Mexican Unidad de Inversion (UDI)"},
+	{"UAH", "980", 2, "Hryvnia"},
+	{"GEL", "981", 2, "Lari"},
+	{"BOV", "984", 2, "Mvdol"},
+	{"PLN", "985", 2, "Zloty"},
+	{"BRL", "986", 2, "Brazilian Real"},
+	{"CLF", "990", 4, "Unidad de Fomento"},
+	{"USN", "997", 2, "US Dollar (Next day)"},
+}
+
+// countries is the ISO 3166-1 list of countries
+var countries = []Country{
+	{"AF", "AFG", "004", "Afghanistan"},
+	{"AL", "ALB", "008", "Albania"},
+	{"AQ", "ATA", "010", "Antarctica"},
+	{"DZ", "DZA", "012", "Algeria"},
+	{"AS", "ASM", "016", "American Samoa"},
+	{"AD", "AND", "020", "Andorra"},
+	{"AO", "AGO", "024", "Angola"},
+	{"AG", "ATG", "028", "Antigua and Barbuda"},
+	{"AZ", "AZE", "031", "Azerbaijan"},
+	{"AR", "ARG", "032", "Argentina"},
+	{"AU", "AUS", "036", "Australia"},
+	{"AT", "AUT", "040", "Austria"},
+	{"BS", "BHS", "044", "Bahamas"},
+	{"BH", "BHR", "048", "Bahrain"},
+	{"BD", "BGD", "050", "Bangladesh"},
+	{"AM", "ARM", "051", "Armenia"},
+	{"BB", "BRB", "052", "Barbados"},
+	{"BE", "BEL", "056", "Belgium"},
+	{"BM", "BMU", "060", "Bermuda"},
+	{"BT", "BTN", "064", "Bhutan"},
+	{"BO", "BOL", "068", "Bolivia"},
+	{"BA", "BIH", "070", "Bosnia and Herzegovina"},
+	{"BW", "BWA", "072", "Botswana"},
+	{"BV", "BVT", "074", "Bouvet Island"},
+	{"BR", "BRA", "076", "Brazil"},
+	{"BZ", "BLZ", "084", "Belize"},
+	{"IO", "IOT", "086", "British Indian Ocean Territory"},
+	{"SB", "SLB", "090", "Solomon Islands"},
+	{"VG", "VGB", "092", "Virgin Islands, British"},
+	{"BN", "BRN", "096", "Brunei Darussalam"},
+	{"BG", "BGR", "100", "Bulgaria"},
+	{"MM", "MMR", "104", "Myanmar"},
+	{"BI", "BDI", "108", "Burundi"},
+	{"BY", "BLR", "112", "Belarus"},
+	{"KH", "KHM", "116", "Cambodia"},
+	{"CM", "CMR", "120", "Cameroon"},
+	{"CA", "CAN", "124", "Canada"},
+	{"CV", "CPV", "132", "Cabo Verde"},
+	{"KY", "CYM", "136", "Cayman Islands"},
+	{"CF", "CAF", "140", "Central African Republic"},
+	{"LK", "LKA", "144", "Sri Lanka"},
+	{"TD", "TCD", "148", "Chad"},
+	{"CL", "CHL", "152", "Chile"},
+	{"CN", "CHN", "156", "China"},
+	{"TW", "TWN", "158", "Taiwan"},
+	{"CX", "CXR", "162", "Christmas Island"},
+	{"CC", "CCK", "166", "Cocos (Keeling) Islands"},
+	{"CO", "COL", "170", "Colombia"},
+	{"KM", "COM", "174", "Comoros"},
+	{"YT", "MYT", "175", "Mayotte"},
+	{"CG", "COG", "178", "Congo"},
+	{"CD", "COD", "180", "Congo, The Democratic Republic of the"},
+	{"CK", "COK", "184", "Cook Islands"},
+	{"CR", "CRI", "188", "Costa Rica"},
+	{"HR", "HRV", "191", "Croatia"},
+	{"CU", "CUB", "192", "Cuba"},
+	{"CY", "CYP", "196", "Cyprus"},
+	{"CZ", "CZE", "203", "Czechia"},
+	{"BJ", "BEN", "204", "Benin"},
+	{"DK", "DNK", "208", "Denmark"},
+	{"DM", "DMA", "212", "Dominica"},
+	{"DO", "DOM", "214", "Dominican Republic"},
+	{"EC", "ECU", "218", "Ecuador"},
+	{"SV", "SLV", "222", "El Salvador"},
+	{"GQ", "GNQ", "226", "Equatorial Guinea"},
+	{"ET", "ETH", "231", "Ethiopia"},
+	{"ER", "ERI", "232", "Eritrea"},
+	{"EE", "EST", "233", "Estonia"},
+	{"FO", "FRO", "234", "Faroe Islands"},
+	{"FK", "FLK", "238", "Falkland Islands (Malvinas)"},
+	{"GS", "SGS", "239", "South Georgia and the South Sandwich Islands"},
+	{"FJ", "FJI", "242", "Fiji"},
+	{"FI", "FIN", "246", "Finland"},
+	{"AX", "ALA", "248", "Åland Islands"},
+	{"FR", "FRA", "250", "France"},
+	{"GF", "GUF", "254", "French Guiana"},
+	{"PF", "PYF", "258", "French Polynesia"},
+	{"TF", "ATF", "260", "French Southern Territories"},
+	{"DJ", "DJI", "262", "Djibouti"},
+	{"GA", "GAB", "266", "Gabon"},
+	{"GE", "GEO", "268", "Georgia"},
+	{"GM", "GMB", "270", "Gambia"},
+	{"PS", "PSE", "275", "Palestine, State of"},
+	{"DE", "DEU", "276", "Germany"},
+	{"GH", "GHA", "288", "Ghana"},
+	{"GI", "GIB", "292", "Gibraltar"},
+	{"KI", "KIR", "296", "Kiribati"},
+	{"GR", "GRC", "300", "Greece"},
+	{"GL", "GRL", "304", "Greenland"},
+	{"GD", "GRD", "308", "Grenada"},
+	{"GP", "GLP", "312", "Guadeloupe"},
+	{"GU", "GUM", "316", "Guam"},
+	{"GT", "GTM", "320", "Guatemala"},
+	{"GN", "GIN", "324", "Guinea"},
+	{"GY", "GUY", "328", "Guyana"},
+	{"HT", "HTI", "332", "Haiti"},
+	{"HM", "HMD", "334", "Heard Island and McDonald Islands"},
+	{"VA", "VAT", "336", "Holy See (Vatican City State)"},
+	{"HN", "HND", "340", "Honduras"},
+	{"HK", "HKG", "344", "Hong Kong"},
+	{"HU", "HUN", "348", "Hungary"},
+	{"IS", "ISL", "352", "Iceland"},
+	{"IN", "IND", "356", "India"},
+	{"ID", "IDN", "360", "Indonesia"},
+	{"IR", "IRN", "364", "Iran"},
+	{"IQ", "IRQ", "368", "Iraq"},
+	{"IE", "IRL", "372", "Ireland"},
+	{"IL", "ISR", "376", "Israel"},
+	{"IT", "ITA", "380", "Italy"},
+	{"CI", "CIV", "384", "Côte d'Ivoire"},
+	{"JM", "JAM", "388", "Jamaica"},
+	{"JP", "JPN", "392", "Japan"},
+	{"KZ", "KAZ", "398", "Kazakhstan"},
+	{"JO", "JOR", "400", "Jordan"},
+	{"KE", "KEN", "404", "Kenya"},
+	{"KP", "PRK", "408", "North Korea"},
+	{"KR", "KOR", "410", "South Korea"},
+	{"KW", "KWT", "414", "Kuwait"},
+	{"KG", "KGZ", "417", "Kyrgyzstan"},
+	{"LA", "LAO", "418", "Laos"},
+	{"LB", "LBN", "422", "Lebanon"},
+	{"LS", "LSO", "426", "Lesotho"},
+	{"LV", "LVA", "428", "Latvia"},
+	{"LR", "LBR", "430", "Liberia"},
+	{"LY", "LBY", "434", "Libya"},
+	{"LI", "LIE", "438", "Liechtenstein"},
+	{"LT", "LTU", "440", "Lithuania"},
+	{"LU", "LUX", "442", "Luxembourg"},
+	{"MO", "MAC", "446", "Macao"},
+	{"MG", "MDG", "450", "Madagascar"},
+	{"MW", "MWI", "454", "Malawi"},
+	{"MY", "MYS", "458", "Malaysia"},
+	{"MV", "MDV", "462", "Maldives"},
+	{"ML", "MLI", "466", "Mali"},
+	{"MT", "MLT", "470", "Malta"},
+	{"MQ", "MTQ", "474", "Martinique"},
+	{"MR", "MRT", "478", "Mauritania"},
+	{"MU", "MUS", "480", "Mauritius"},
+	{"MX", "MEX", "484", "Mexico"},
+	{"MC", "MCO", "492", "Monaco"},
+	{"MN", "MNG", "496", "Mongolia"},
+	{"MD", "MDA", "498", "Moldova"},
+	{"ME", "MNE", "499", "Montenegro"},
+	{"MS", "MSR", "500", "Montserrat"},
+	{"MA", "MAR", "504", "Morocco"},
+	{"MZ", "MOZ", "508", "Mozambique"},
+	{"OM", "OMN", "512", "Oman"},
+	{"NA", "NAM", "516", "Namibia"},
+	{"NR", "NRU", "520", "Nauru"},
+	{"NP", "NPL", "524", "Nepal"},
+	{"NL", "NLD", "528", "Netherlands"},
+	{"CW", "CUW", "531", "Curaçao"},
+	{"AW", "ABW", "533", "Aruba"},
+	{"SX", "SXM", "534", "Sint Maarten (Dutch part)"},
+	{"BQ", "BES", "535", "Bonaire, Sint Eustatius and Saba"},
+	{"NC", "NCL", "540", "New Caledonia"},
+	{"VU", "VUT", "548", "Vanuatu"},
+	{"NZ", "NZL", "554", "New Zealand"},
+	{"NI", "NIC", "558", "Nicaragua"},
+	{"NE", "NER", "562", "Niger"},
+	{"NG", "NGA", "566", "Nigeria"},
+	{"NU", "NIU", "570", "Niue"},
+	{"NF", "NFK", "574", "Norfolk Island"},
+	{"NO", "NOR", "578", "Norway"},
+	{"MP", "MNP", "580", "Northern Mariana Islands"},
+	{"UM", "UMI", "581", "United States Minor Outlying Islands"},
+	{"FM", "FSM", "583", "Micronesia, Federated States of"},
+	{"MH", "MHL", "584", "Marshall Islands"},
+	{"PW", "PLW", "585", "Palau"},
+	{"PK", "PAK", "586", "Pakistan"},
+	{"PA", "PAN", "591", "Panama"},
+	{"PG", "PNG", "598", "Papua New Guinea"},
+	{"PY", "PRY", "600", "Paraguay"},
+	{"PE", "PER", "604", "Peru"},
+	{"PH", "PHL", "608", "Philippines"},
+	{"PN", "PCN", "612", "Pitcairn"},
+	{"PL", "POL", "616", "Poland"},
+	{"PT", "PRT", "620", "Portugal"},
+	{"GW", "GNB", "624", "Guinea-Bissau"},
+	{"TL", "TLS", "626", "Timor-Leste"},
+	{"PR", "PRI", "630", "Puerto Rico"},
+	{"QA", "QAT", "634", "Qatar"},
+	{"RE", "REU", "638", "Réunion"},
+	{"RO", "ROU", "642", "Romania"},
+	{"RU", "RUS", "643", "Russian Federation"},
+	{"RW", "RWA", "646", "Rwanda"},
+	{"BL", "BLM", "652", "Saint Barthélemy"},
+	{"SH", "SHN", "654", "Saint Helena, Ascension and Tristan da Cunha"},
+	{"KN", "KNA", "659", "Saint Kitts and Nevis"},
+	{"AI", "AIA", "660", "Anguilla"},
+	{"LC", "LCA", "662", "Saint Lucia"},
+	{"MF", "MAF", "663", "Saint Martin (French part)"},
+	{"PM", "SPM", "666", "Saint Pierre and Miquelon"},
+	{"VC", "VCT", "670", "Saint Vincent and the Grenadines"},
+	{"SM", "SMR", "674", "San Marino"},
+	{"ST", "STP", "678", "Sao Tome and Principe"},
+	{"SA", "SAU", "682", "Saudi Arabia"},
+	{"SN", "SEN", "686", "Senegal"},
+	{"RS", "SRB", "688", "Serbia"},
+	{"SC", "SYC", "690", "Seychelles"},
+	{"SL", "SLE", "694", "Sierra Leone"},
+	{"SG", "SGP", "702", "Singapore"},
+	{"SK", "SVK", "703", "Slovakia"},
+	{"VN", "VNM", "704", "Vietnam"},
+	{"SI", "SVN", "705", "Slovenia"},
+	{"SO", "SOM", "706", "Somalia"},
+	{"ZA", "ZAF", "710", "South Africa"},
+	{"ZW", "ZWE", "716", "Zimbabwe"},
+	{"ES", "ESP", "724", "Spain"},
+	{"SS", "SSD", "728", "South Sudan"},
+	{"SD", "SDN", "729", "Sudan"},
+	{"EH", "ESH", "732", "Western Sahara"},
+	{"SR", "SUR", "740", "Suriname"},
+	{"SJ", "SJM", "744", "Svalbard and Jan Mayen"},
+	{"SZ", "SWZ", "748", "Eswatini"},
+	{"SE", "SWE", "752", "Sweden"},
+	{"CH", "CHE", "756", "Switzerland"},
+	{"SY", "SYR", "760", "Syria"},
+	{"TJ", "TJK", "762", "Tajikistan"},
+	{"TH", "THA", "764", "Thailand"},
+	{"TG", "TGO", "768", "Togo"},
+	{"TK", "TKL", "772", "Tokelau"},
+	{"TO", "TON", "776", "Tonga"},
+	{"TT", "TTO", "780", "Trinidad and Tobago"},
+	{"AE", "ARE", "784", "United Arab Emirates"},
+	{"TN", "TUN", "788", "Tunisia"},
+	{"TR", "TUR", "792", "Türkiye"},
+	{"TM", "TKM", "795", "Turkmenistan"},
+	{"TC", "TCA", "796", "Turks and Caicos Islands"},
+	{"TV", "TUV", "798", "Tuvalu"},
+	{"UG", "UGA", "800", "Uganda"},
+	{"UA", "UKR", "804", "Ukraine"},
+	{"MK", "MKD", "807", "North Macedonia"},
+	{"EG", "EGY", "818", "Egypt"},
+	{"GB", "GBR", "826", "United Kingdom"},
+	{"GG", "GGY", "831", "Guernsey"},
+	{"JE", "JEY", "832", "Jersey"},
+	{"IM", "IMN", "833", "Isle of Man"},
+	{"TZ", "TZA", "834", "Tanzania"},
+	{"US", "USA", "840", "United States"},
+	{"VI", "VIR", "850", "Virgin Islands, U.S."},
+	{"BF", "BFA", "854", "Burkina Faso"},
+	{"UY", "URY", "858", "Uruguay"},
+	{"UZ", "UZB", "860", "Uzbekistan"},
+	{"VE", "VEN", "862", "Venezuela"},
+	{"WF", "WLF", "876", "Wallis and Futuna"},
+	{"WS", "WSM", "882", "Samoa"},
+	{"YE", "YEM", "887", "Yemen"},
+	{"ZM", "ZMB", "894", "Zambia"},
+}
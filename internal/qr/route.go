@@ -0,0 +1,84 @@
+package qr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoRoute is returned by Route when the QR has no account the payer supports
+var ErrNoRoute = errors.New("no merchant account for the supported schemes")
+
+// RouteResult is the merchant account chosen by Route and why it was chosen
+type RouteResult struct {
+	Account SchemeAccount
+	Reason  string
+}
+
+// PaymentAccounts lists the merchant accounts of q in tag order: the card scheme
+// accounts of tags 02-25 and the merchant account templates of tags 26-51.
+// Tags 26-51 that are not templates have no known scheme and are left out.
+func (q *QR) PaymentAccounts() ([]SchemeAccount, error) {
+	m, err := ConvertQRToMap(q)
+	if err != nil {
+		return nil, err
+	}
+	accounts := schemeAccounts(m)
+	for i := 26; i <= 51; i++ {
+		t := templateAt(m, fmt.Sprintf("%02d", i))
+		if t.GUID == "" {
+			continue
+		}
+		accounts = append(accounts, SchemeAccount{Tag: t.ID, Scheme: SchemeForGUID(t.GUID), Value: m[t.ID]})
+	}
+	return accounts, nil
+}
+
+// Route picks the merchant account of q a payer app should pay, supported lists
+// the schemes of the app in its order of preference. Within a scheme the account
+// with the lowest tag, the merchant's primary one, is chosen among those whose
+// merchant PAN passes the Luhn check. An account with an invalid PAN is only
+// chosen when its scheme has no valid one, the reason tells it.
+func Route(q *QR, supported []Scheme) (RouteResult, error) {
+	accounts, err := q.PaymentAccounts()
+	if err != nil {
+		return RouteResult{}, err
+	}
+	var missing []string
+	for i, s := range supported {
+		var invalid []SchemeAccount
+		for _, a := range accounts {
+			if a.Scheme != s {
+				continue
+			}
+			if s.UsesPAN() && !validPAN(a.Value) {
+				invalid = append(invalid, a)
+				continue
+			}
+			reason := routeReason(a, i, len(supported), missing)
+			for _, skipped := range invalid {
+				reason += "; skipped the invalid PAN at tag " + skipped.Tag
+			}
+			return RouteResult{Account: a, Reason: reason}, nil
+		}
+		if len(invalid) > 0 {
+			a := invalid[0]
+			reason := routeReason(a, i, len(supported), missing) + "; its merchant PAN fails the Luhn check and no valid " + string(s) + " account was found"
+			return RouteResult{Account: a, Reason: reason}, nil
+		}
+		missing = append(missing, string(s))
+	}
+	return RouteResult{}, ErrNoRoute
+}
+
+// routeReason tells why a, of the payer's choice i of n, was chosen
+func routeReason(a SchemeAccount, i, n int, missing []string) string {
+	reason := fmt.Sprintf("%s at tag %s is the payer's choice %d of %d", a.Scheme, a.Tag, i+1, n)
+	if i == 0 {
+		reason = fmt.Sprintf("%s at tag %s is the payer's preferred scheme", a.Scheme, a.Tag)
+	}
+	if len(missing) > 0 {
+		reason += "; the QR has no " + strings.Join(missing, ", ") + " account"
+	}
+	return reason
+}
@@ -0,0 +1,103 @@
+package qr
+
+import (
+	"testing"
+)
+
+func routeQR(accounts ...SchemeAccount) *QR {
+	q := &QR{CountryCode: "TH"}
+	q.Merchant.ID.Schemes = accounts
+	return q
+}
+
+func TestRoute(t *testing.T) {
+	const (
+		visa       = "4111111111111111"
+		visaBad    = "4111111111111112"
+		mastercard = "5555555555554444"
+		unionPay   = "3430076400520446000000000011156"
+	)
+	tests := []struct {
+		name      string
+		q         *QR
+		supported []Scheme
+		tag       string
+		reason    string
+	}{
+		{
+			name:      "preferred scheme",
+			q:         routeQR(SchemeAccount{Tag: "02", Value: visa}, SchemeAccount{Tag: "04", Value: mastercard}),
+			supported: []Scheme{SchemeMastercard, SchemeVisa},
+			tag:       "04",
+			reason:    "Mastercard at tag 04 is the payer's preferred scheme",
+		},
+		{
+			name:      "next choice",
+			q:         routeQR(SchemeAccount{Tag: "02", Value: visa}),
+			supported: []Scheme{SchemeJCB, SchemeMastercard, SchemeVisa},
+			tag:       "02",
+			reason:    "Visa at tag 02 is the payer's choice 3 of 3; the QR has no JCB, Mastercard account",
+		},
+		{
+			name:      "primary tag first",
+			q:         routeQR(SchemeAccount{Tag: "03", Value: "4000000000000002"}, SchemeAccount{Tag: "02", Value: visa}),
+			supported: []Scheme{SchemeVisa},
+			tag:       "02",
+		},
+		{
+			name:      "valid account before an invalid primary one",
+			q:         routeQR(SchemeAccount{Tag: "02", Value: visaBad}, SchemeAccount{Tag: "03", Value: visa}),
+			supported: []Scheme{SchemeVisa},
+			tag:       "03",
+			reason:    "Visa at tag 03 is the payer's preferred scheme; skipped the invalid PAN at tag 02",
+		},
+		{
+			name:      "invalid account as a fallback",
+			q:         routeQR(SchemeAccount{Tag: "02", Value: visaBad}, SchemeAccount{Tag: "04", Value: mastercard}),
+			supported: []Scheme{SchemeVisa, SchemeMastercard},
+			tag:       "02",
+			reason:    "Visa at tag 02 is the payer's preferred scheme; its merchant PAN fails the Luhn check and no valid Visa account was found",
+		},
+		{
+			name:      "scheme without PANs",
+			q:         routeQR(SchemeAccount{Tag: "15", Value: unionPay}),
+			supported: []Scheme{SchemeUnionPay},
+			tag:       "15",
+			reason:    "UnionPay at tag 15 is the payer's preferred scheme",
+		},
+		{
+			name: "merchant account template",
+			q: func() *QR {
+				q := routeQR(SchemeAccount{Tag: "02", Value: visa})
+				q.Merchant.ID.PromptPay = QRMerchantIDPromptPay{AID: GUIDPromptPay, MobileNumber: "0066812345678"}
+				return q
+			}(),
+			supported: []Scheme{SchemePromptPay, SchemeVisa},
+			tag:       "29",
+			reason:    "PromptPay at tag 29 is the payer's preferred scheme",
+		},
+	}
+	for _, tt := range tests {
+		res, err := Route(tt.q, tt.supported)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if res.Account.Tag != tt.tag {
+			t.Errorf("%s: routed to tag %s, want %s", tt.name, res.Account.Tag, tt.tag)
+		}
+		if tt.reason != "" && res.Reason != tt.reason {
+			t.Errorf("%s: reason %q, want %q", tt.name, res.Reason, tt.reason)
+		}
+	}
+}
+
+func TestRouteNoRoute(t *testing.T) {
+	q := routeQR(SchemeAccount{Tag: "02", Value: "4111111111111111"})
+	if _, err := Route(q, []Scheme{SchemeJCB, SchemeAmex}); err != ErrNoRoute {
+		t.Errorf("got %v, want ErrNoRoute", err)
+	}
+	if _, err := Route(q, nil); err != ErrNoRoute {
+		t.Errorf("no supported scheme: got %v, want ErrNoRoute", err)
+	}
+}
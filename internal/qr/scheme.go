@@ -0,0 +1,179 @@
+package qr
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Scheme is the card network owning a Merchant Account Information tag between 02 and 25
+type Scheme string
+
+// Card schemes of the EMVCo reserved ranges
+const (
+	SchemeVisa       Scheme = "Visa"             // 02-03
+	SchemeMastercard Scheme = "Mastercard"       // 04-05
+	SchemeEMVCo      Scheme = "EMVCo"            // 06-08 and 17-25
+	SchemeDiscover   Scheme = "Discover"         // 09-10
+	SchemeAmex       Scheme = "American Express" // 11-12
+	SchemeJCB        Scheme = "JCB"              // 13-14
+	SchemeUnionPay   Scheme = "UnionPay"         // 15-16
+)
+
+// Schemes of the Thai QR Payment merchant account templates, other templates
+// of tags 26-51 use their GUID as scheme, see SchemeForGUID
+const (
+	SchemePromptPay    Scheme = "PromptPay"              // GUIDPromptPay
+	SchemeBillPayment  Scheme = "PromptPay Bill Payment" // GUIDBillPayment
+	SchemePromptPayAPI Scheme = "PromptPay API"          // GUIDPromptPayAPI
+)
+
+// SchemeAccount is a merchant account of a scheme, a card scheme account of
+// tags 02-25 or a merchant account template of tags 26-51
+type SchemeAccount struct {
+	Tag    string // 02-51
+	Scheme Scheme // set from Tag on decode, checked against it on encode
+	Value  string // merchant PAN or scheme specific merchant identifier, the template value for 26-51
+}
+
+// SchemeForTag returns the card scheme of a Merchant Account Information tag between 02 and 25
+func SchemeForTag(tag string) (Scheme, bool) {
+	n, err := strconv.Atoi(tag)
+	if err != nil || len(tag) != 2 {
+		return "", false
+	}
+	switch {
+	case n >= 2 && n <= 3:
+		return SchemeVisa, true
+	case n >= 4 && n <= 5:
+		return SchemeMastercard, true
+	case n >= 6 && n <= 8, n >= 17 && n <= 25:
+		return SchemeEMVCo, true
+	case n >= 9 && n <= 10:
+		return SchemeDiscover, true
+	case n >= 11 && n <= 12:
+		return SchemeAmex, true
+	case n >= 13 && n <= 14:
+		return SchemeJCB, true
+	case n >= 15 && n <= 16:
+		return SchemeUnionPay, true
+	}
+	return "", false
+}
+
+// SchemeTags returns the tags of a card scheme in priority order, the first being its primary tag
+func SchemeTags(s Scheme) []string {
+	var tags []string
+	for i := 2; i <= 25; i++ {
+		tag := fmt.Sprintf("%02d", i)
+		if scheme, _ := SchemeForTag(tag); scheme == s {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// SchemeForGUID returns the scheme of a merchant account template, the GUID
+// itself when it is not a Thai QR Payment template
+func SchemeForGUID(guid string) Scheme {
+	switch guid {
+	case GUIDPromptPay:
+		return SchemePromptPay
+	case GUIDBillPayment:
+		return SchemeBillPayment
+	case GUIDPromptPayAPI:
+		return SchemePromptPayAPI
+	}
+	return Scheme(guid)
+}
+
+// UsesPAN reports whether the merchant accounts of the scheme are PANs of 8 to
+// 19 digits passing the Luhn check, as for Visa and Mastercard. UnionPay uses
+// the acquirer and forwarding IINs followed by the merchant ID, and the EMVCo
+// tags have no defined content.
+func (s Scheme) UsesPAN() bool {
+	switch s {
+	case SchemeVisa, SchemeMastercard, SchemeDiscover, SchemeAmex, SchemeJCB:
+		return true
+	}
+	return false
+}
+
+// Luhn reports whether s is made of digits and ends with its Luhn (mod 10) check digit
+func Luhn(s string) bool {
+	if !IsDigits(s) {
+		return false
+	}
+	sum := 0
+	for i := range s {
+		d := int(s[len(s)-1-i] - '0')
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validPAN reports whether s is a PAN of 8 to 19 digits passing the Luhn check
+func validPAN(s string) bool {
+	return len(s) >= 8 && len(s) <= 19 && Luhn(s)
+}
+
+// schemeAccounts reads the tags 02-25 of m in ascending order
+func schemeAccounts(m map[string]string) []SchemeAccount {
+	var accounts []SchemeAccount
+	for i := 2; i <= 25; i++ {
+		tag := fmt.Sprintf("%02d", i)
+		if m[tag] == "" {
+			continue
+		}
+		scheme, _ := SchemeForTag(tag)
+		accounts = append(accounts, SchemeAccount{Tag: tag, Scheme: scheme, Value: m[tag]})
+	}
+	return accounts
+}
+
+// writeSchemeAccounts writes accounts into m, where the legacy fields of
+// QRMerchantID may already have set some of the tags
+func writeSchemeAccounts(m map[string]string, accounts []SchemeAccount) error {
+	for _, a := range accounts {
+		scheme, ok := SchemeForTag(a.Tag)
+		if !ok {
+			return tagError(a.Tag, ErrBadTag, "card scheme tag between 02 and 25", a.Tag)
+		}
+		if a.Scheme != "" && a.Scheme != scheme {
+			return tagError(a.Tag, ErrBadValue, string(scheme), string(a.Scheme))
+		}
+		if m[a.Tag] != "" && m[a.Tag] != a.Value {
+			return fmt.Errorf("card scheme tag %s is set twice with %q and %q", a.Tag, m[a.Tag], a.Value)
+		}
+		m[a.Tag] = a.Value
+	}
+	return nil
+}
+
+// validateSchemes checks the card scheme accounts, merchant PANs must pass the Luhn check, see Scheme.UsesPAN
+func validateSchemes(r *Report, accounts []SchemeAccount) {
+	for _, a := range accounts {
+		scheme, ok := SchemeForTag(a.Tag)
+		switch {
+		case !ok:
+			r.add(a.Tag, SeverityError, RuleScheme, "card scheme accounts must use tags 02 to 25")
+			continue
+		case a.Scheme != "" && a.Scheme != scheme:
+			r.add(a.Tag, SeverityError, RuleScheme, "tag %s belongs to %s but is set for %s", a.Tag, scheme, a.Scheme)
+		}
+		if !scheme.UsesPAN() || a.Value == "" {
+			continue
+		}
+		if !IsDigits(a.Value) {
+			r.add(a.Tag, SeverityWarning, RuleScheme, "%s merchant account %q is not a PAN", scheme, a.Value)
+		} else if len(a.Value) < 8 || len(a.Value) > 19 {
+			r.add(a.Tag, SeverityError, RuleScheme, "%s merchant PAN must be 8 to 19 digits but got %d", scheme, len(a.Value))
+		} else if !Luhn(a.Value) {
+			r.add(a.Tag, SeverityError, RuleScheme, "%s merchant PAN %s fails the Luhn check", scheme, a.Value)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package qr
+
+import (
+	"testing"
+)
+
+func TestSchemeForTag(t *testing.T) {
+	tests := []struct {
+		tags    []string
+		scheme  Scheme
+		usesPAN bool
+	}{
+		{[]string{"02", "03"}, SchemeVisa, true},
+		{[]string{"04", "05"}, SchemeMastercard, true},
+		{[]string{"06", "07", "08", "17", "18", "19", "20", "21", "22", "23", "24", "25"}, SchemeEMVCo, false},
+		{[]string{"09", "10"}, SchemeDiscover, true},
+		{[]string{"11", "12"}, SchemeAmex, true},
+		{[]string{"13", "14"}, SchemeJCB, true},
+		{[]string{"15", "16"}, SchemeUnionPay, false},
+	}
+	for _, tt := range tests {
+		for _, tag := range tt.tags {
+			if s, ok := SchemeForTag(tag); !ok || s != tt.scheme {
+				t.Errorf("SchemeForTag(%s) = %q, %v, want %q", tag, s, ok, tt.scheme)
+			}
+		}
+		if got := SchemeTags(tt.scheme); len(got) != len(tt.tags) || got[0] != tt.tags[0] {
+			t.Errorf("SchemeTags(%s) = %v, want %v", tt.scheme, got, tt.tags)
+		}
+		if tt.scheme.UsesPAN() != tt.usesPAN {
+			t.Errorf("%s.UsesPAN() = %v, want %v", tt.scheme, !tt.usesPAN, tt.usesPAN)
+		}
+	}
+	for _, tag := range []string{"00", "01", "26", "51", "2", "ab", ""} {
+		if s, ok := SchemeForTag(tag); ok {
+			t.Errorf("SchemeForTag(%q) = %q, want no card scheme", tag, s)
+		}
+	}
+	for _, s := range []Scheme{SchemePromptPay, SchemeBillPayment, SchemePromptPayAPI, Scheme("ID.CO.QRIS.WWW")} {
+		if s.UsesPAN() {
+			t.Errorf("%s.UsesPAN() = true", s)
+		}
+	}
+}
+
+func TestValidateSchemes(t *testing.T) {
+	tests := []struct {
+		tag, value string
+		severity   Severity
+		issue      bool
+	}{
+		{"02", "4111111111111111", 0, false},
+		{"03", "4111111111111112", SeverityError, true},
+		{"04", "5555555555554444", 0, false},
+		{"05", "520473000001046", SeverityError, true},
+		{"04", "5555", SeverityError, true},
+		{"04", "MC-MERCHANT-1", SeverityWarning, true},
+		{"09", "6011111111111117", 0, false},
+		{"10", "6011111111111118", SeverityError, true},
+		{"11", "378282246310005", 0, false},
+		{"12", "378282246310006", SeverityError, true},
+		{"13", "3530111333300000", 0, false},
+		{"14", "3530111333300001", SeverityError, true},
+		{"15", "3430076400520446000000000011156", 0, false},
+		{"16", "1234", 0, false},
+		{"17", "ANY EMVCO VALUE", 0, false},
+		{"25", "12345", 0, false},
+	}
+	for _, tt := range tests {
+		var r Report
+		validateSchemes(&r, []SchemeAccount{{Tag: tt.tag, Value: tt.value}})
+		if len(r.Issues) > 0 != tt.issue {
+			t.Errorf("tag %s %q: issues %v", tt.tag, tt.value, r.Issues)
+			continue
+		}
+		if tt.issue && r.Issues[0].Severity != tt.severity {
+			t.Errorf("tag %s %q: %v, want a %v", tt.tag, tt.value, r.Issues[0], tt.severity)
+		}
+	}
+
+	var r Report
+	validateSchemes(&r, []SchemeAccount{{Tag: "02", Scheme: SchemeMastercard, Value: "4111111111111111"}, {Tag: "30", Value: "x"}})
+	if len(r.Issues) != 2 {
+		t.Errorf("scheme mismatch and bad tag: issues %v", r.Issues)
+	}
+}
+
+func TestLuhn(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"4111111111111111", true},
+		{"79927398713", true},
+		{"79927398710", false},
+		{"0", true},
+		{"", false},
+		{"4111 1111 1111 1111", false},
+	}
+	for _, tt := range tests {
+		if got := Luhn(tt.s); got != tt.want {
+			t.Errorf("Luhn(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
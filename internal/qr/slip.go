@@ -0,0 +1,168 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/howeyc/crc16"
+)
+
+// SlipAPIID is the API ID (00.00) of the verification QR of Thai mobile banking slips
+const SlipAPIID = "000001"
+
+// SlipQR is the verification mini-QR printed on Thai mobile banking e-slips.
+// Unlike merchant QRs its CRC is in tag 91, the last tag, and covers the
+// payload up to "9104".
+type SlipQR struct {
+	APIID       string // 00.00; Mandatory, "000001"
+	SendingBank string // 00.01; Mandatory, 3 digits bank code such as "014"
+	TransRef    string // 00.02; Mandatory, transaction reference of the sending bank
+	CountryCode string // 51; Mandatory, "TH"
+	CRC         string // 91; Mandatory
+}
+
+// thaiBanks are the bank codes of the Bank of Thailand used in SlipQR.SendingBank
+var thaiBanks = map[string]string{
+	"002": "Bangkok Bank",
+	"004": "Kasikornbank",
+	"006": "Krungthai Bank",
+	"011": "TMBThanachart Bank",
+	"014": "Siam Commercial Bank",
+	"022": "CIMB Thai Bank",
+	"024": "United Overseas Bank (Thai)",
+	"025": "Bank of Ayudhya",
+	"030": "Government Savings Bank",
+	"033": "Government Housing Bank",
+	"034": "Bank for Agriculture and Agricultural Cooperatives",
+	"035": "Export-Import Bank of Thailand",
+	"066": "Islamic Bank of Thailand",
+	"067": "Tisco Bank",
+	"069": "Kiatnakin Phatra Bank",
+	"070": "ICBC (Thai)",
+	"071": "Thai Credit Bank",
+	"073": "Land and Houses Bank",
+	"098": "Small and Medium Enterprise Development Bank of Thailand",
+}
+
+// BankName returns the name of the sending bank, "" when the code is unknown
+func (s *SlipQR) BankName() string {
+	return thaiBanks[s.SendingBank]
+}
+
+// IsSlip reports whether s looks like a slip verification QR rather than a merchant QR
+func IsSlip(s string) bool {
+	p, err := Parse(s)
+	if err != nil || p.Get("91") == nil {
+		return false
+	}
+	n := p.Get("00")
+	if n == nil {
+		return false
+	}
+	nodes, err := parseNodes(n.Value, 0, "00")
+	if err != nil {
+		return false
+	}
+	api := findNode(nodes, "00")
+	return api != nil && api.Value == SlipAPIID
+}
+
+// DecodeSlip parses and checks a slip verification QR. Errors are returned as *ParseError.
+func DecodeSlip(s string) (*SlipQR, error) {
+	p, err := Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	crc := p.Get("91")
+	if crc == nil {
+		return nil, &ParseError{Offset: len(s), Path: "91", Err: ErrMissingTag}
+	}
+	if last := p.Nodes[len(p.Nodes)-1]; crc != last {
+		return nil, &ParseError{Offset: crc.Offset, Path: "91", Expected: "last tag", Actual: "followed by tag " + last.ID, Err: ErrBadTag}
+	}
+	if err := checkSlipCRC(s[:crc.Offset+4], crc.Value); err != nil {
+		err.Offset = crc.Offset
+		return nil, err
+	}
+	api := p.Get("00")
+	if api == nil {
+		return nil, &ParseError{Offset: 0, Path: "00", Err: ErrMissingTag}
+	}
+	nodes, err := parseNodes(api.Value, api.Offset+4, "00")
+	if err != nil {
+		return nil, err
+	}
+	api.Children = nodes
+	slip := &SlipQR{
+		APIID:       valueOf(api.Get("00")),
+		SendingBank: valueOf(api.Get("01")),
+		TransRef:    valueOf(api.Get("02")),
+		CountryCode: valueOf(p.Get("51")),
+		CRC:         crc.Value,
+	}
+	if err := slip.check(); err != nil {
+		return nil, withOffset(p, err)
+	}
+	return slip, nil
+}
+
+// check returns a *ParseError, without offset, for the first invalid field
+func (s *SlipQR) check() error {
+	for _, t := range []struct{ tag, value string }{
+		{"00.00", s.APIID},
+		{"00.01", s.SendingBank},
+		{"00.02", s.TransRef},
+		{"51", s.CountryCode},
+	} {
+		if t.value == "" {
+			return &ParseError{Offset: -1, Path: t.tag, Err: ErrMissingTag}
+		}
+	}
+	if s.APIID != SlipAPIID {
+		return tagError("00.00", ErrBadValue, SlipAPIID, s.APIID)
+	}
+	if len(s.SendingBank) != 3 || !IsDigits(s.SendingBank) {
+		return tagError("00.01", ErrBadValue, "3 digits bank code", s.SendingBank)
+	}
+	if s.CountryCode != "TH" {
+		return tagError("51", ErrUnsupportedCountry, "TH", s.CountryCode)
+	}
+	return nil
+}
+
+// checkSlipCRC checks crc against str, the payload up to and including "9104"
+func checkSlipCRC(str, crc string) *ParseError {
+	want := crc16.ChecksumCCITTFalse([]byte(str))
+	if v, err := strconv.ParseUint(crc, 16, 16); err != nil || len(crc) != 4 || uint16(v) != want {
+		return tagError("91", ErrCRCMismatch, fmt.Sprintf("%04X", want), crc)
+	}
+	return nil
+}
+
+// EncodeSlip serializes s and computes its CRC. An empty APIID or CountryCode
+// defaults to "000001" and "TH", CRC is ignored.
+func EncodeSlip(s *SlipQR) (string, error) {
+	slip := *s
+	if slip.APIID == "" {
+		slip.APIID = SlipAPIID
+	}
+	if slip.CountryCode == "" {
+		slip.CountryCode = "TH"
+	}
+	if err := slip.check(); err != nil {
+		return "", err
+	}
+	var api, str bytes.Buffer
+	writeSubTag(&api, "00", slip.APIID)
+	writeSubTag(&api, "01", slip.SendingBank)
+	writeSubTag(&api, "02", slip.TransRef)
+	if utf8.RuneCount(api.Bytes()) > 99 {
+		return "", lengthError("00", 99, api.String())
+	}
+	writeSubTag(&str, "00", api.String())
+	writeSubTag(&str, "51", slip.CountryCode)
+	str.WriteString("9104")
+	return str.String() + fmt.Sprintf("%04X", crc16.ChecksumCCITTFalse(str.Bytes())), nil
+}
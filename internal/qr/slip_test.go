@@ -0,0 +1,102 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// slipPayloads are slip verification QRs in the layout printed by Thai mobile banking apps
+var slipPayloads = []struct {
+	payload, bank, transRef, bankName string
+}{
+	{"0027000600000101030140206ABC1235102TH91042E29", "014", "ABC123", "Siam Commercial Bank"},
+	{"00390006000001010300402180162511035123456785102TH91048F58", "004", "016251103512345678", "Kasikornbank"},
+	{"00450006000001010301402242025110312345678ABCD12345102TH9104DD12", "014", "2025110312345678ABCD1234", "Siam Commercial Bank"},
+	{"0040000600000101030020219202511031530BBL00015102TH91045432", "002", "202511031530BBL0001", "Bangkok Bank"},
+	{"0038000600000101030250217BAY202511030001235102TH9104124D", "025", "BAY20251103000123", "Bank of Ayudhya"},
+	{"0027000600000101031400206ABC1235102TH9104E600", "140", "ABC123", ""},
+}
+
+func TestDecodeSlip(t *testing.T) {
+	for _, tt := range slipPayloads {
+		if !IsSlip(tt.payload) {
+			t.Errorf("IsSlip(%s) = false", tt.payload)
+		}
+		s, err := DecodeSlip(tt.payload)
+		if err != nil {
+			t.Errorf("DecodeSlip(%s) error = %v", tt.payload, err)
+			continue
+		}
+		if s.APIID != SlipAPIID || s.SendingBank != tt.bank || s.TransRef != tt.transRef || s.CountryCode != "TH" || s.CRC != tt.payload[len(tt.payload)-4:] {
+			t.Errorf("DecodeSlip(%s) = %+v", tt.payload, s)
+		}
+		if s.BankName() != tt.bankName {
+			t.Errorf("DecodeSlip(%s).BankName() = %q, want %q", tt.payload, s.BankName(), tt.bankName)
+		}
+	}
+	if IsSlip(decoderPayloads[1].payload) {
+		t.Errorf("IsSlip(%s) = true for a merchant QR", decoderPayloads[1].payload)
+	}
+}
+
+func TestEncodeSlip(t *testing.T) {
+	for _, tt := range slipPayloads {
+		got, err := EncodeSlip(&SlipQR{SendingBank: tt.bank, TransRef: tt.transRef, CRC: "FFFF"})
+		if err != nil {
+			t.Errorf("EncodeSlip(%s, %s) error = %v", tt.bank, tt.transRef, err)
+			continue
+		}
+		if got != tt.payload {
+			t.Errorf("EncodeSlip(%s, %s) = %s, want %s", tt.bank, tt.transRef, got, tt.payload)
+		}
+	}
+
+	tests := []struct {
+		slip SlipQR
+		path string
+		err  error
+	}{
+		{SlipQR{SendingBank: "14", TransRef: "ABC123"}, "00.01", ErrBadValue},
+		{SlipQR{SendingBank: "01A", TransRef: "ABC123"}, "00.01", ErrBadValue},
+		{SlipQR{SendingBank: "014"}, "00.02", ErrMissingTag},
+		{SlipQR{TransRef: "ABC123"}, "00.01", ErrMissingTag},
+		{SlipQR{APIID: "000002", SendingBank: "014", TransRef: "ABC123"}, "00.00", ErrBadValue},
+		{SlipQR{SendingBank: "014", TransRef: "ABC123", CountryCode: "US"}, "51", ErrUnsupportedCountry},
+		{SlipQR{SendingBank: "014", TransRef: strings.Repeat("9", 80)}, "00", ErrBadLength},
+	}
+	for _, tt := range tests {
+		_, err := EncodeSlip(&tt.slip)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Path != tt.path || !errors.Is(err, tt.err) {
+			t.Errorf("EncodeSlip(%+v) error = %v, want %v at %s", tt.slip, err, tt.err, tt.path)
+		}
+	}
+}
+
+func TestDecodeSlipErrors(t *testing.T) {
+	const valid = "0027000600000101030140206ABC1235102TH91042E29"
+	tests := []struct {
+		name    string
+		payload string
+		path    string
+		offset  int
+		err     error
+	}{
+		{"data after the CRC", valid + "0201X", "91", 37, ErrBadTag},
+		{"tag after the CRC", valid + "5102TH", "91", 37, ErrBadTag},
+		{"missing CRC", valid[:37], "91", 37, ErrMissingTag},
+		{"CRC mismatch", valid[:41] + "2E28", "91", 37, ErrCRCMismatch},
+		{"truncated", valid[:20], "00", 0, ErrTruncated},
+		{"API ID", "0027000600000201030140206ABC1235102TH91044395", "00.00", 4, ErrBadValue},
+		{"country", "0027000600000101030140206ABC1235102US9104832C", "51", 31, ErrUnsupportedCountry},
+		{"missing country", "0027000600000101030140206ABC12391046624", "51", -1, ErrMissingTag},
+	}
+	for _, tt := range tests {
+		_, err := DecodeSlip(tt.payload)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.Path != tt.path || pe.Offset != tt.offset || !errors.Is(err, tt.err) {
+			t.Errorf("%s: DecodeSlip() error = %v, want %v at %s offset %d", tt.name, err, tt.err, tt.path, tt.offset)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package qr
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+)
+
+// NewDecoder returns a Decoder reading newline-delimited QR payloads from r.
+// Blank lines and the spaces around a payload are skipped. Its fields are
+// those of a zero Decoder until they are set.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// More reports whether there is another payload to decode
+func (d *Decoder) More() bool {
+	d.fill()
+	return d.next != ""
+}
+
+// Decode decodes the next payload into q with DecodeString. It returns io.EOF
+// when the reader is exhausted. A payload that cannot be decoded returns its
+// *ParseError and leaves q as it was, the next call goes on with the next payload.
+func (d *Decoder) Decode(q *QR) error {
+	if d.r == nil {
+		return errors.New("qr: Decode needs a Decoder made by NewDecoder")
+	}
+	d.fill()
+	if d.next == "" {
+		return d.err
+	}
+	s := d.next
+	d.next = ""
+	res, err := d.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*q = *res
+	return nil
+}
+
+// fill reads ahead the next non-blank line
+func (d *Decoder) fill() {
+	if d.r == nil {
+		return
+	}
+	for d.next == "" && d.err == nil {
+		line, err := d.r.ReadString('\n')
+		d.next = strings.TrimSpace(line)
+		d.err = err
+	}
+}
+
+// CRCPolicy is how an Encoder writes the CRC (63)
+type CRCPolicy int
+
+const (
+	CRCGenerate CRCPolicy = iota // compute the CRC, QR.CRC is ignored
+	CRCVerify                    // compute the CRC, a QR.CRC that is set must match it
+)
+
+// Encoder writes QR payloads to an output stream, one per Encode call
+// followed by the separator
+type Encoder struct {
+	w         io.Writer
+	separator string
+	crc       CRCPolicy
+	profile   Profile
+}
+
+// NewEncoder returns an Encoder writing to w with a "\n" separator and generated CRCs
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, separator: "\n"}
+}
+
+// SetSeparator sets what is written after each payload, such as "\r\n"
+func (e *Encoder) SetSeparator(sep string) {
+	e.separator = sep
+}
+
+// SetCRCPolicy sets how the CRC is written
+func (e *Encoder) SetCRCPolicy(policy CRCPolicy) {
+	e.crc = policy
+}
+
+// SetProfile makes Encode check each payload against the rules of p, nil disables the check
+func (e *Encoder) SetProfile(p Profile) {
+	e.profile = p
+}
+
+// Encode converts q with ConvertQRToMap and ConvertMapToString and writes it.
+// Nothing is written when q cannot be encoded or breaks the profile.
+func (e *Encoder) Encode(q *QR) error {
+	m, err := ConvertQRToMap(q)
+	if err != nil {
+		return err
+	}
+	delete(m, "63")
+	s, err := ConvertMapToString(m)
+	if err != nil {
+		return err
+	}
+	if crc := s[len(s)-4:]; e.crc == CRCVerify && q.CRC != "" && !strings.EqualFold(q.CRC, crc) {
+		return tagError("63", ErrCRCMismatch, crc, q.CRC)
+	}
+	if e.profile != nil {
+		p, err := Parse(s)
+		if err != nil {
+			return err
+		}
+		if err := e.profile.Check(p); err != nil {
+			return withOffset(p, err)
+		}
+	}
+	_, err = io.WriteString(e.w, s+e.separator)
+	return err
+}
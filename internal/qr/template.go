@@ -0,0 +1,208 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Template is a template data object identified by its Globally Unique
+// Identifier in sub-tag 00, such as the Merchant Account Information
+// templates (26-51) and the Unreserved Templates (80-99).
+type Template struct {
+	ID     string            // tag ID of the template
+	GUID   string            // 00; Globally Unique Identifier
+	Fields map[string]string // sub-tags 01-99
+	Value  interface{}       // set by the TemplateDecoder registered for GUID
+}
+
+// TemplateDecoder converts the sub-tags of a template into a typed value
+type TemplateDecoder func(t Template) (interface{}, error)
+
+// TemplateEncoder converts a typed value back into the sub-tags 01-99 of a template
+type TemplateEncoder func(v interface{}) (map[string]string, error)
+
+var (
+	templateMu       sync.RWMutex
+	templateDecoders = map[string]TemplateDecoder{}
+	templateEncoders = map[string]TemplateEncoder{}
+)
+
+// RegisterTemplate registers dec to decode the templates with the given GUID.
+// It is meant to be called from init functions, registering a GUID twice replaces the decoder.
+func RegisterTemplate(guid string, dec TemplateDecoder) {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	templateDecoders[guid] = dec
+}
+
+// RegisterTemplateEncoder registers enc to encode the Value of the templates with the given GUID,
+// registering a GUID twice replaces the encoder
+func RegisterTemplateEncoder(guid string, enc TemplateEncoder) {
+	templateMu.Lock()
+	defer templateMu.Unlock()
+	templateEncoders[guid] = enc
+}
+
+func templateDecoder(guid string) TemplateDecoder {
+	templateMu.RLock()
+	defer templateMu.RUnlock()
+	return templateDecoders[guid]
+}
+
+func templateEncoder(guid string) TemplateEncoder {
+	templateMu.RLock()
+	defer templateMu.RUnlock()
+	return templateEncoders[guid]
+}
+
+// splitTemplate splits value into its GUID and fields
+func splitTemplate(id, value string) (Template, error) {
+	nodes, err := parseNodes(value, 0, id)
+	if err != nil {
+		if pe, ok := err.(*ParseError); ok {
+			pe.Offset = -1 // offset is relative to the template value
+		}
+		return Template{}, err
+	}
+	t := Template{ID: id, Fields: map[string]string{}}
+	for _, n := range nodes {
+		if n.ID == "00" {
+			t.GUID = n.Value
+		} else {
+			t.Fields[n.ID] = n.Value
+		}
+	}
+	return t, nil
+}
+
+// decodeTemplate splits value into its GUID and fields and runs the registered decoder
+func decodeTemplate(id, value string) (Template, error) {
+	t, err := splitTemplate(id, value)
+	if err != nil {
+		return Template{}, err
+	}
+	if dec := templateDecoder(t.GUID); dec != nil {
+		if t.Value, err = dec(t); err != nil {
+			return Template{}, err
+		}
+	}
+	return t, nil
+}
+
+// unreservedTemplates reads the tags 80-99 of m in ascending order
+func unreservedTemplates(m map[string]string) ([]Template, error) {
+	var templates []Template
+	for i := 80; i <= 99; i++ {
+		id := fmt.Sprintf("%02d", i)
+		if m[id] == "" {
+			continue
+		}
+		t, err := decodeTemplate(id, m[id])
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// String serializes the value of the template: GUID followed by the fields in ascending order
+func (t Template) String() string {
+	var str bytes.Buffer
+	writeSubTag(&str, "00", t.GUID)
+	keys := make([]string, 0, len(t.Fields))
+	for k := range t.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeSubTag(&str, k, t.Fields[k])
+	}
+	return str.String()
+}
+
+// Encode serializes the template like String. When Value is set and an encoder
+// is registered for GUID, the sub-tags are taken from Value and Fields is ignored.
+func (t Template) Encode() (string, error) {
+	if t.Value == nil {
+		return t.String(), nil
+	}
+	enc := templateEncoder(t.GUID)
+	if enc == nil {
+		return t.String(), nil
+	}
+	fields, err := enc(t.Value)
+	if err != nil {
+		return "", err
+	}
+	t.Fields = fields
+	return t.String(), nil
+}
+
+// writeUnreservedTemplates writes templates into m. Templates without ID
+// take the first IDs from 80 not used by the others.
+func writeUnreservedTemplates(m map[string]string, templates []Template) error {
+	return writeTemplates(m, templates, 80, 99, "unreserved template")
+}
+
+// writeTemplates writes templates with IDs between from and to into m, templates
+// without ID take the first free IDs from from. A tag already set in m may only
+// be set again by a template carrying at least its GUID and sub-tags.
+func writeTemplates(m map[string]string, templates []Template, from, to int, kind string) error {
+	for _, t := range templates {
+		if t.ID == "" {
+			continue
+		}
+		if n, err := strconv.Atoi(t.ID); err != nil || len(t.ID) != 2 || n < from || n > to {
+			return fmt.Errorf("%s ID must be between %02d and %02d, got %q", kind, from, to, t.ID)
+		}
+		value, err := t.Encode()
+		if err != nil {
+			return err
+		}
+		if m[t.ID] != "" && !coversTemplate(t.ID, value, m[t.ID]) {
+			return fmt.Errorf("%s %s is set twice with different values", kind, t.ID)
+		}
+		m[t.ID] = value
+	}
+	next := from
+	for _, t := range templates {
+		if t.ID != "" {
+			continue
+		}
+		for ; next <= to && m[fmt.Sprintf("%02d", next)] != ""; next++ {
+		}
+		if next > to {
+			return fmt.Errorf("no free %s ID left for GUID %s", kind, t.GUID)
+		}
+		value, err := t.Encode()
+		if err != nil {
+			return err
+		}
+		m[fmt.Sprintf("%02d", next)] = value
+	}
+	return nil
+}
+
+// coversTemplate reports whether the template value carries every sub-tag of
+// the template old with the same value, old being the same template written
+// from a typed field that knows fewer sub-tags
+func coversTemplate(id, value, old string) bool {
+	t, err := splitTemplate(id, value)
+	if err != nil {
+		return false
+	}
+	o, err := splitTemplate(id, old)
+	if err != nil || o.GUID != t.GUID {
+		return false
+	}
+	for k, v := range o.Fields {
+		if t.Fields[k] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,35 @@
+package qr
+
+// Values of Tip or Convenience Indicator (55)
+const (
+	TipPrompt                = "01" // the payer is prompted to enter a tip
+	ConvenienceFeeFixed      = "02" // a fixed fee is given in tag 56
+	ConvenienceFeePercentage = "03" // a percentage fee is given in tag 57
+)
+
+// PayableAmount returns the amount the payer has to pay: the transaction
+// amount plus the convenience fee, or plus tip when the payer is prompted for one.
+// tip is ignored for any other indicator.
+func (t QRTransaction) PayableAmount(tip Amount) (Amount, error) {
+	if !t.Amount.IsSet() {
+		return Amount{}, tagError("54", ErrMissingTag, "amount", "")
+	}
+	switch t.TipOrConvenienceIndicator {
+	case "":
+		return t.Amount, nil
+	case TipPrompt:
+		return t.Amount.Add(tip)
+	case ConvenienceFeeFixed:
+		if !t.ConvenienceFeeFixed.IsSet() {
+			return Amount{}, tagError("56", ErrMissingTag, "fixed convenience fee", "")
+		}
+		return t.Amount.Add(t.ConvenienceFeeFixed)
+	case ConvenienceFeePercentage:
+		fee, err := t.Amount.Percent(t.ConvenienceFeePercentage)
+		if err != nil {
+			return Amount{}, tagError("57", ErrBadValue, "percentage", t.ConvenienceFeePercentage)
+		}
+		return t.Amount.Add(fee)
+	}
+	return Amount{}, tagError("55", ErrBadValue, "01, 02 or 03", t.TipOrConvenienceIndicator)
+}
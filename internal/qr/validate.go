@@ -0,0 +1,436 @@
+package qr
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Severity of a validation issue
+type Severity int
+
+const (
+	SeverityError Severity = iota
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Rule codes reported in Issue.Rule
+const (
+	RuleParse     = "parse"
+	RuleMandatory = "mandatory"
+	RuleLength    = "length"
+	RuleFormat    = "format"
+	RuleAID       = "aid"
+	RuleCountry   = "country"
+	RuleCurrency  = "currency"
+	RulePOIAmount = "poi-amount"
+	RuleTip       = "tip"
+	RuleLanguage  = "language"
+	RuleChannel   = "channel"
+	RuleProxy     = "proxy"
+	RuleBiller    = "biller"
+	RuleCRC       = "crc"
+	RuleMCC       = "mcc"
+	RuleScheme    = "scheme"
+)
+
+// Issue is a single problem found by Validate
+type Issue struct {
+	Tag      string // tag path such as "62.05"
+	Severity Severity
+	Rule     string
+	Message  string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s [%s] tag %s: %s", i.Severity, i.Rule, i.Tag, i.Message)
+}
+
+// Report holds every issue found by Validate or ValidateString
+type Report struct {
+	Issues []Issue
+}
+
+// Valid reports whether no issue of error severity was found
+func (r *Report) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Errors returns the issues of error severity
+func (r *Report) Errors() []Issue {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the issues of warning severity
+func (r *Report) Warnings() []Issue {
+	return r.filter(SeverityWarning)
+}
+
+func (r *Report) filter(sev Severity) []Issue {
+	var issues []Issue
+	for _, i := range r.Issues {
+		if i.Severity == sev {
+			issues = append(issues, i)
+		}
+	}
+	return issues
+}
+
+func (r *Report) add(tag string, sev Severity, rule string, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, Issue{Tag: tag, Severity: sev, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+var (
+	amountFormat = regexp.MustCompile(`^[0-9]+(\.[0-9]*)?$`)
+	crcFormat    = regexp.MustCompile(`^[0-9A-F]{4}$`)
+	alphaFormat  = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+// Validate checks every field of qr and reports all problems found instead of
+// stopping at the first one. An empty CRC or Point of Initiation Method is not
+// reported as it is generated on encode.
+func Validate(qr *QR) *Report {
+	r := &Report{}
+	validate(r, qr)
+	return r
+}
+
+// ValidateString parses s and reports the same problems as Validate plus the CRC check
+func ValidateString(s string) *Report {
+	r := &Report{}
+	p, err := Parse(s)
+	if err != nil {
+		tag := ""
+		if pe, ok := err.(*ParseError); ok {
+			tag = pe.Path
+		}
+		r.add(tag, SeverityError, RuleParse, "%v", err)
+		return r
+	}
+
+	m := make(map[string]string, len(p.Nodes))
+	for _, n := range p.Nodes {
+		m[n.ID] = n.Value
+	}
+	for _, id := range templateTags {
+		if m[id] == "" {
+			continue
+		}
+		if _, err := subMap(m, id); err != nil {
+			r.add(id, SeverityError, RuleParse, "template cannot be parsed: %v", err)
+		}
+	}
+
+	if crc := p.Get("63"); crc == nil {
+		r.add("63", SeverityError, RuleMandatory, "CRC is missing")
+	} else if crc != p.Nodes[len(p.Nodes)-1] {
+		r.add("63", SeverityError, RuleCRC, "CRC must be the last tag")
+	} else if err := checkCRC(s[:crc.Offset+4], crc.Value); err != nil {
+		pe := err.(*ParseError)
+		r.add("63", SeverityError, RuleCRC, "CRC mismatch, expected %s but got %s", pe.Expected, pe.Actual)
+	}
+
+	exponent := CurrencyExponent(m["53"])
+	for _, id := range []string{"54", "56"} {
+		if m[id] == "" {
+			continue
+		}
+		if _, err := ParseAmount(m[id], exponent); err != nil {
+			r.add(id, SeverityError, RuleFormat, "%v", err)
+		}
+	}
+
+	qr, _ := fillQR(m)
+	validate(r, qr)
+	return r
+}
+
+func validate(r *Report, qr *QR) {
+	// Mandatory tags
+	mandatory := []struct {
+		tag, value, name string
+	}{
+		{"00", qr.PayloadFormatIndicator, "Payload Format Indicator"},
+		{"52", qr.Merchant.CategoryCode, "Merchant Category Code"},
+		{"53", qr.Transaction.CurrencyCode, "Transaction Currency"},
+		{"58", qr.CountryCode, "Country Code"},
+		{"59", qr.Merchant.Name, "Merchant Name"},
+		{"60", qr.Merchant.City, "Merchant City"},
+	}
+	for _, t := range mandatory {
+		if t.value == "" {
+			r.add(t.tag, SeverityError, RuleMandatory, "%s is mandatory", t.name)
+		}
+	}
+	if !hasMerchantAccount(qr) {
+		r.add("02-51", SeverityError, RuleMandatory, "at least one Merchant Account Information is mandatory")
+	}
+
+	// Lengths
+	exact := []struct {
+		tag, value string
+		length     int
+	}{
+		{"00", qr.PayloadFormatIndicator, 2},
+		{"52", qr.Merchant.CategoryCode, 4},
+		{"53", qr.Transaction.CurrencyCode, 3},
+		{"58", qr.CountryCode, 2},
+		{"63", qr.CRC, 4},
+	}
+	for _, t := range exact {
+		if l := utf8.RuneCountInString(t.value); t.value != "" && l != t.length {
+			r.add(t.tag, SeverityError, RuleLength, "length must be %d but got %d", t.length, l)
+		}
+	}
+	amount, _ := qr.Transaction.Amount.MarshalEMV()
+	fee, _ := qr.Transaction.ConvenienceFeeFixed.MarshalEMV()
+	max := []struct {
+		tag, value string
+		length     int
+	}{
+		{"54", amount, 13},
+		{"56", fee, 13},
+		{"57", qr.Transaction.ConvenienceFeePercentage, 5},
+		{"59", qr.Merchant.Name, 25},
+		{"60", qr.Merchant.City, 15},
+	}
+	for _, t := range max {
+		if l := utf8.RuneCountInString(t.value); l > t.length {
+			r.add(t.tag, SeverityError, RuleLength, "length must not be longer than %d but got %d", t.length, l)
+		}
+	}
+	if m, err := ConvertQRToMap(qr); err == nil {
+		tags := make([]string, 0, len(m))
+		for tag := range m {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		for _, tag := range tags {
+			if l := utf8.RuneCountInString(m[tag]); l > 99 {
+				r.add(tag, SeverityError, RuleLength, "length must not be longer than 99 but got %d", l)
+			}
+		}
+	}
+
+	// Formats
+	if qr.PayloadFormatIndicator != "" && qr.PayloadFormatIndicator != "01" {
+		r.add("00", SeverityError, RuleFormat, "Payload Format Indicator must be \"01\" but got %q", qr.PayloadFormatIndicator)
+	}
+	if qr.PointOfInitiationMethod != "" && !qr.PointOfInitiationMethod.Valid() {
+		r.add("01", SeverityError, RuleFormat, "Point of Initiation Method must be \"11\" or \"12\" but got %q", qr.PointOfInitiationMethod)
+	}
+	numeric := []struct{ tag, value string }{
+		{"52", qr.Merchant.CategoryCode},
+		{"53", qr.Transaction.CurrencyCode},
+	}
+	for _, t := range numeric {
+		if t.value != "" && !IsDigits(t.value) {
+			r.add(t.tag, SeverityError, RuleFormat, "must be numeric but got %q", t.value)
+		}
+	}
+	exponent := CurrencyExponent(qr.Transaction.CurrencyCode)
+	for _, a := range []struct {
+		tag    string
+		amount Amount
+	}{{"54", qr.Transaction.Amount}, {"56", qr.Transaction.ConvenienceFeeFixed}} {
+		if a.amount.IsSet() && a.amount.Exponent() != exponent {
+			r.add(a.tag, SeverityError, RuleFormat, "amount must have %d decimals for currency %s but has %d", exponent, qr.Transaction.CurrencyCode, a.amount.Exponent())
+		}
+	}
+	if qr.Transaction.Amount.IsSet() && qr.Transaction.Amount.IsZero() {
+		r.add("54", SeverityWarning, RuleFormat, "amount is 0")
+	}
+	if qr.Transaction.ConvenienceFeePercentage != "" && !amountFormat.MatchString(qr.Transaction.ConvenienceFeePercentage) {
+		r.add("57", SeverityError, RuleFormat, "percentage must be digits with an optional \".\" but got %q", qr.Transaction.ConvenienceFeePercentage)
+	}
+	if qr.CountryCode != "" && !alphaFormat.MatchString(qr.CountryCode) {
+		r.add("58", SeverityError, RuleFormat, "country code must be 2 upper case letters but got %q", qr.CountryCode)
+	}
+	if qr.CRC != "" && !crcFormat.MatchString(qr.CRC) {
+		r.add("63", SeverityError, RuleFormat, "CRC must be 4 upper case hex digits but got %q", qr.CRC)
+	}
+	for _, t := range []struct{ tag, value string }{{"59", qr.Merchant.Name}, {"60", qr.Merchant.City}} {
+		if strings.IndexFunc(t.value, func(c rune) bool { return c < 0x20 || c > 0x7E }) >= 0 {
+			r.add(t.tag, SeverityWarning, RuleFormat, "should only contain printable Latin characters but got %q", t.value)
+		}
+	}
+
+	// Globally unique identifiers
+	if pp := qr.Merchant.ID.PromptPay; pp != (QRMerchantIDPromptPay{}) && pp.AID != GUIDPromptPay {
+		r.add("29.00", SeverityError, RuleAID, "AID must be %s but got %q", GUIDPromptPay, pp.AID)
+	}
+	if _, err := normalizePromptPay(qr.Merchant.ID.PromptPay); err != nil {
+		pe := err.(*ParseError)
+		r.add(pe.Path, SeverityError, RuleProxy, "expected %s but got %q", pe.Expected, pe.Actual)
+	}
+	if bp := qr.Merchant.ID.PromptPayBillPayment; bp != (QRMerchantIDPromptPayBillPayment{}) && bp.AID != GUIDBillPayment {
+		r.add("30.00", SeverityError, RuleAID, "AID must be %s but got %q", GUIDBillPayment, bp.AID)
+	}
+
+	if bp := qr.Merchant.ID.PromptPayBillPayment; bp != (QRMerchantIDPromptPayBillPayment{}) {
+		if !ValidBillerID(bp.BillerID) {
+			r.add("30.01", SeverityError, RuleBiller, "Biller ID must be a 13 digits tax ID and a 2 digits suffix but got %q", bp.BillerID)
+		}
+		if bp.Reference1 == "" {
+			r.add("30.02", SeverityError, RuleMandatory, "Reference 1 is mandatory in tag 30")
+		} else if !ValidBillReference(bp.Reference1) {
+			r.add("30.02", SeverityError, RuleBiller, "Reference 1 must be 1 to 20 upper case letters or digits but got %q", bp.Reference1)
+		}
+		if bp.Reference2 != "" && !ValidBillReference(bp.Reference2) {
+			r.add("30.03", SeverityError, RuleBiller, "Reference 2 must be 1 to 20 upper case letters or digits but got %q", bp.Reference2)
+		}
+	}
+
+	if c := qr.Merchant.CategoryCode; c != "" && len(c) == 4 && IsDigits(c) {
+		if _, ok := LookupMCC(c); !ok {
+			r.add("52", SeverityError, RuleMCC, "%q is not an ISO 18245 Merchant Category Code", c)
+		}
+	}
+	if qr.CountryCode != "" {
+		if !allowedCountry(qr.CountryCode, nil) {
+			r.add("58", SeverityError, RuleCountry, "%q is not an ISO 3166 alpha-2 country code", qr.CountryCode)
+		} else if qr.CountryCode != "TH" {
+			r.add("58", SeverityWarning, RuleCountry, "country code %q is not TH", qr.CountryCode)
+		}
+	}
+	if qr.Transaction.CurrencyCode != "" {
+		if !allowedCurrency(qr.Transaction.CurrencyCode, nil) {
+			r.add("53", SeverityError, RuleCurrency, "%q is not an ISO 4217 numeric currency code", qr.Transaction.CurrencyCode)
+		} else if qr.Transaction.CurrencyCode != "764" {
+			r.add("53", SeverityWarning, RuleCurrency, "currency %q is not Thai baht (764)", qr.Transaction.CurrencyCode)
+		}
+	}
+
+	if m, err := marshalMap(qr.Merchant.ID); err == nil {
+		validateSchemes(r, schemeAccounts(m))
+	}
+	validateSchemes(r, qr.Merchant.ID.Schemes)
+	validateTip(r, qr.Transaction)
+	validateLanguage(r, qr.Merchant.Language)
+	validateAdditionalData(r, qr.AdditionalData)
+	for _, t := range qr.Merchant.ID.Accounts {
+		if t.ID != "" && (len(t.ID) != 2 || t.ID < "26" || t.ID > "51") {
+			r.add(t.ID, SeverityError, RuleFormat, "merchant account template ID must be between 26 and 51")
+		}
+		if t.GUID == "" {
+			r.add(t.ID+".00", SeverityError, RuleMandatory, "Globally Unique Identifier is mandatory in merchant account templates")
+		}
+	}
+	for _, t := range qr.UnreservedTemplates {
+		if t.ID != "" && (len(t.ID) != 2 || t.ID < "80" || t.ID > "99") {
+			r.add(t.ID, SeverityError, RuleFormat, "unreserved template ID must be between 80 and 99")
+		}
+		if t.GUID == "" {
+			r.add(t.ID+".00", SeverityError, RuleMandatory, "Globally Unique Identifier is mandatory in unreserved templates")
+		}
+	}
+
+	// Point of Initiation Method versus amount
+	switch {
+	case qr.PointOfInitiationMethod == POIStatic && qr.Transaction.Amount.IsSet():
+		r.add("01", SeverityWarning, RulePOIAmount, "static QR (11) carries an amount, use dynamic (12)")
+	case qr.PointOfInitiationMethod == POIDynamic && !qr.Transaction.Amount.IsSet():
+		r.add("54", SeverityError, RulePOIAmount, "dynamic QR (12) must carry an amount")
+	}
+	if qr.PointOfInitiationMethod == POIDynamic && !hasReference(qr) {
+		r.add("01", SeverityWarning, RulePOIAmount, "dynamic QR (12) should carry a unique reference to be reconciled")
+	}
+}
+
+func hasMerchantAccount(qr *QR) bool {
+	id := qr.Merchant.ID
+	return len(id.Schemes) > 0 || len(id.Accounts) > 0 || id.Visa != "" || id.MasterCard != "" || id.CUP != "" || id.JCB != "" || id.UnionPay != "" ||
+		id.EMVCo != "" || id.AMEX != "" || id.TPN != "" || id.PromptCard != "" || id.VisaLocal != "" ||
+		id.PromptPay != (QRMerchantIDPromptPay{}) || id.PromptPayBillPayment != (QRMerchantIDPromptPayBillPayment{}) ||
+		id.API != (QRMerchantIDPromptPayAPI{}) || qr.DataObjectForMerchantAccountInformationByMasterCard != ""
+}
+
+// validateTip checks the cross-field rules of tags 55, 56 and 57
+func validateTip(r *Report, t QRTransaction) {
+	switch t.TipOrConvenienceIndicator {
+	case "", TipPrompt:
+	case ConvenienceFeeFixed:
+		if !t.ConvenienceFeeFixed.IsSet() {
+			r.add("56", SeverityError, RuleTip, "Value of Convenience Fee Fixed is mandatory when tag 55 is \"02\"")
+		}
+	case ConvenienceFeePercentage:
+		if t.ConvenienceFeePercentage == "" {
+			r.add("57", SeverityError, RuleTip, "Value of Convenience Fee Percentage is mandatory when tag 55 is \"03\"")
+		} else if pct, err := ParseAmount(t.ConvenienceFeePercentage, 2); err == nil && (pct.Minor() < 1 || pct.Minor() > 9999) {
+			r.add("57", SeverityError, RuleTip, "percentage must be between 00.01 and 99.99 but got %q", t.ConvenienceFeePercentage)
+		}
+	default:
+		r.add("55", SeverityError, RuleTip, "Tip or Convenience Indicator must be \"01\", \"02\" or \"03\" but got %q", t.TipOrConvenienceIndicator)
+	}
+	if t.ConvenienceFeeFixed.IsSet() && t.TipOrConvenienceIndicator != ConvenienceFeeFixed {
+		r.add("56", SeverityError, RuleTip, "Value of Convenience Fee Fixed is only allowed when tag 55 is \"02\"")
+	}
+	if t.ConvenienceFeePercentage != "" && t.TipOrConvenienceIndicator != ConvenienceFeePercentage {
+		r.add("57", SeverityError, RuleTip, "Value of Convenience Fee Percentage is only allowed when tag 55 is \"03\"")
+	}
+}
+
+// validateLanguage checks the Merchant Information Language Template (64)
+func validateLanguage(r *Report, l QRMerchantLanguage) {
+	if l == (QRMerchantLanguage{}) {
+		return
+	}
+	if l.LanguagePreference == "" {
+		r.add("64.00", SeverityError, RuleMandatory, "Language Preference is mandatory in tag 64")
+	} else if !alphaFormat.MatchString(strings.ToUpper(l.LanguagePreference)) {
+		r.add("64.00", SeverityError, RuleLanguage, "Language Preference must be a 2 letters ISO 639 code but got %q", l.LanguagePreference)
+	}
+	if l.Name == "" {
+		r.add("64.01", SeverityError, RuleMandatory, "Merchant Name - Alternate Language is mandatory in tag 64")
+	} else if n := utf8.RuneCountInString(l.Name); n > 25 {
+		r.add("64.01", SeverityError, RuleLength, "length must not be longer than 25 but got %d", n)
+	}
+	if n := utf8.RuneCountInString(l.City); n > 15 {
+		r.add("64.02", SeverityError, RuleLength, "length must not be longer than 15 but got %d", n)
+	}
+}
+
+// validateAdditionalData checks the Additional Data Field Template (62)
+func validateAdditionalData(r *Report, a QRAdditionalData) {
+	fields := []struct {
+		tag, value string
+		max        int
+	}{
+		{"62.01", a.BillNumber, 25}, {"62.02", a.MobileNumber, 25}, {"62.03", a.StoreID, 25},
+		{"62.04", a.LoyaltyNumber, 25}, {"62.05", a.ReferenceID, 25}, {"62.06", a.ConsumerID, 25},
+		{"62.07", a.TerminalID, 25}, {"62.08", a.PurposeOfTransaction, 25}, {"62.09", a.AdditionalConsumerDataRequest, 3},
+		{"62.10", a.MerchantTaxID, 20},
+	}
+	for _, f := range fields {
+		if l := utf8.RuneCountInString(f.value); l > f.max {
+			r.add(f.tag, SeverityError, RuleLength, "length must not be longer than %d but got %d", f.max, l)
+		}
+	}
+	if a.MerchantTaxID != "" && !ValidThaiID(a.MerchantTaxID) {
+		r.add("62.10", SeverityWarning, RuleFormat, "Thai Merchant Tax ID should be 13 digits with a valid check digit but got %q", a.MerchantTaxID)
+	}
+	if a.MerchantChannel != "" {
+		if _, err := ParseMerchantChannel(a.MerchantChannel); err != nil {
+			r.add("62.11", SeverityError, RuleChannel, "invalid Merchant Channel: %v", err)
+		}
+	}
+	ids := make([]string, 0, len(a.PaymentSystemSpecific))
+	for id := range a.PaymentSystemSpecific {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if len(id) != 2 || id < "50" || id > "99" {
+			r.add("62."+id, SeverityError, RuleFormat, "payment system specific sub-tag must be between 50 and 99")
+		}
+	}
+}
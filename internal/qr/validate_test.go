@@ -0,0 +1,131 @@
+package qr
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// validQR returns a static PromptPay QR without any issue
+func validQR() *QR {
+	return &QR{
+		PayloadFormatIndicator:  "01",
+		PointOfInitiationMethod: POIStatic,
+		Merchant: QRMerchant{
+			ID:           QRMerchantID{PromptPay: QRMerchantIDPromptPay{AID: GUIDPromptPay, MobileNumber: "0066812345678"}},
+			CategoryCode: "5814",
+			Name:         "SHOP",
+			City:         "BANGKOK",
+		},
+		Transaction: QRTransaction{CurrencyCode: "764"},
+		CountryCode: "TH",
+	}
+}
+
+func amount(t *testing.T, s string) Amount {
+	t.Helper()
+	a, err := ParseAmount(s, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestValidateCrossField(t *testing.T) {
+	type want struct {
+		tag  string
+		rule string
+		sev  Severity
+	}
+	tests := []struct {
+		name   string
+		change func(q *QR)
+		want   []want // every issue, in order
+	}{
+		{"valid", func(q *QR) {}, nil},
+
+		// 55, 56 and 57
+		{"tip prompt", func(q *QR) { q.Transaction.TipOrConvenienceIndicator = TipPrompt }, nil},
+		{"fixed fee", func(q *QR) {
+			q.Transaction.TipOrConvenienceIndicator = ConvenienceFeeFixed
+			q.Transaction.ConvenienceFeeFixed = amount(t, "10.00")
+		}, nil},
+		{"percentage fee", func(q *QR) {
+			q.Transaction.TipOrConvenienceIndicator = ConvenienceFeePercentage
+			q.Transaction.ConvenienceFeePercentage = "3.5"
+		}, nil},
+		{"fixed fee missing", func(q *QR) { q.Transaction.TipOrConvenienceIndicator = ConvenienceFeeFixed },
+			[]want{{"56", RuleTip, SeverityError}}},
+		{"percentage missing", func(q *QR) { q.Transaction.TipOrConvenienceIndicator = ConvenienceFeePercentage },
+			[]want{{"57", RuleTip, SeverityError}}},
+		{"percentage 0", func(q *QR) {
+			q.Transaction.TipOrConvenienceIndicator = ConvenienceFeePercentage
+			q.Transaction.ConvenienceFeePercentage = "0"
+		}, []want{{"57", RuleTip, SeverityError}}},
+		{"percentage 100", func(q *QR) {
+			q.Transaction.TipOrConvenienceIndicator = ConvenienceFeePercentage
+			q.Transaction.ConvenienceFeePercentage = "100"
+		}, []want{{"57", RuleTip, SeverityError}}},
+		{"fixed fee without 55", func(q *QR) { q.Transaction.ConvenienceFeeFixed = amount(t, "10.00") },
+			[]want{{"56", RuleTip, SeverityError}}},
+		{"percentage with a fixed fee", func(q *QR) {
+			q.Transaction.TipOrConvenienceIndicator = ConvenienceFeeFixed
+			q.Transaction.ConvenienceFeeFixed = amount(t, "10.00")
+			q.Transaction.ConvenienceFeePercentage = "3"
+		}, []want{{"57", RuleTip, SeverityError}}},
+		{"unknown indicator", func(q *QR) { q.Transaction.TipOrConvenienceIndicator = "04" },
+			[]want{{"55", RuleTip, SeverityError}}},
+
+		// Point of Initiation Method
+		{"static with an amount", func(q *QR) { q.Transaction.Amount = amount(t, "100") },
+			[]want{{"01", RulePOIAmount, SeverityWarning}}},
+		{"dynamic", func(q *QR) {
+			q.PointOfInitiationMethod = POIDynamic
+			q.Transaction.Amount = amount(t, "100")
+			q.AdditionalData.ReferenceID = "INV001"
+		}, nil},
+		{"dynamic without amount", func(q *QR) {
+			q.PointOfInitiationMethod = POIDynamic
+			q.AdditionalData.BillNumber = "INV001"
+		}, []want{{"54", RulePOIAmount, SeverityError}}},
+		{"dynamic without reference", func(q *QR) {
+			q.PointOfInitiationMethod = POIDynamic
+			q.Transaction.Amount = amount(t, "100")
+		}, []want{{"01", RulePOIAmount, SeverityWarning}}},
+
+		// Merchant Information - Language Template
+		{"language", func(q *QR) {
+			q.Merchant.Language = QRMerchantLanguage{"th", "ร้านค้า", "กรุงเทพ"}
+		}, nil},
+		{"language without preference", func(q *QR) { q.Merchant.Language.Name = "ร้านค้า" },
+			[]want{{"64.00", RuleMandatory, SeverityError}}},
+		{"language not ISO 639", func(q *QR) { q.Merchant.Language = QRMerchantLanguage{LanguagePreference: "T1", Name: "SHOP"} },
+			[]want{{"64.00", RuleLanguage, SeverityError}}},
+		{"language without name", func(q *QR) { q.Merchant.Language.LanguagePreference = "TH" },
+			[]want{{"64.01", RuleMandatory, SeverityError}}},
+		{"language name too long", func(q *QR) {
+			q.Merchant.Language = QRMerchantLanguage{LanguagePreference: "TH", Name: strings.Repeat("ก", 26), City: strings.Repeat("ก", 16)}
+		}, []want{{"64.01", RuleLength, SeverityError}, {"64.02", RuleLength, SeverityError}}},
+
+		// Additional Data Field Template
+		{"payment system specific", func(q *QR) {
+			q.AdditionalData.PaymentSystemSpecific = map[string]string{"99": "x", "49": "x", "12": "x", "50": "x", "5": "x"}
+		}, []want{{"62.12", RuleFormat, SeverityError}, {"62.49", RuleFormat, SeverityError}, {"62.5", RuleFormat, SeverityError}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := validQR()
+			tt.change(q)
+			// maps are walked in a random order, the issues must not be
+			for i := 0; i < 10; i++ {
+				var got []want
+				for _, issue := range Validate(q).Issues {
+					got = append(got, want{issue.Tag, issue.Rule, issue.Severity})
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("got %v, want %v", Validate(q).Issues, tt.want)
+				}
+			}
+		})
+	}
+}
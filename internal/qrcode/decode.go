@@ -0,0 +1,500 @@
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// Errors returned by Decode
+var (
+	ErrNotFound = errors.New("qrcode: no QR code found")
+	ErrFormat   = errors.New("qrcode: format or version information cannot be read")
+	ErrChecksum = errors.New("qrcode: too many errors to correct")
+	ErrData     = errors.New("qrcode: malformed data segments")
+)
+
+// Result is a QR Code symbol read from an image
+type Result struct {
+	Payload   []byte
+	Version   int
+	Level     Level
+	Mask      int
+	Corrected int  // codewords restored by Reed-Solomon error correction
+	Mirrored  bool // the symbol was read from its mirror image
+	Inverted  bool // the symbol is light on a dark background
+	ECI       int  // Extended Channel Interpretation of the payload, -1 when not given
+}
+
+// Decode finds a QR Code symbol in img and returns its payload. Rotated and
+// perspective-warped symbols are read as long as the three finder patterns are
+// visible and the near side of the symbol is at most 1.3 times as long as the
+// far side, 1.5 times up to version 7. A quiet zone is not required.
+func Decode(img image.Image) (*Result, error) {
+	lum, w, h := luminance(img)
+	err := ErrNotFound
+	for _, local := range []bool{false, true} {
+		for _, invert := range []bool{false, true} {
+			b := binarize(lum, w, h, local, invert)
+			res, e := b.decode()
+			if e == nil {
+				res.Inverted = invert
+				return res, nil
+			}
+			if e != ErrNotFound {
+				err = e
+			}
+		}
+	}
+	return nil, err
+}
+
+// decode tries the candidate finder triples of b, most likely first
+func (b *bitmap) decode() (*Result, error) {
+	err := ErrNotFound
+	for i, t := range triples(findFinders(b)) {
+		if i == 8 {
+			break
+		}
+		res, e := b.decodeAt(t)
+		if e == nil {
+			return res, nil
+		}
+		err = e
+	}
+	return nil, err
+}
+
+// decodeAt samples and decodes the symbol whose finder patterns are t
+func (b *bitmap) decodeAt(t triple) (*Result, error) {
+	module := b.moduleSize(t)
+	dim := (int(math.Floor(distance(t.tl.point, t.tr.point)/module+0.5))+
+		int(math.Floor(distance(t.tl.point, t.bl.point)/module+0.5)))/2 + 7
+	switch dim % 4 {
+	case 0:
+		dim++
+	case 2:
+		dim--
+	case 3:
+		dim -= 2
+	}
+	err := ErrNotFound
+	for _, size := range []int{dim, dim + 4, dim - 4} {
+		if size < 21 || size > 177 {
+			continue
+		}
+		res, e := b.decodeSize(t, size, module)
+		if e == nil {
+			return res, nil
+		}
+		err = e
+	}
+	return nil, err
+}
+
+func (b *bitmap) decodeSize(t triple, size int, module float64) (*Result, error) {
+	corners := fourthCorners(t)
+	err := ErrNotFound
+	for i, at := range corners {
+		if i > 0 && distance(at, corners[0]) < module {
+			continue
+		}
+		res, e := b.decodeFrom(t, size, module, at)
+		if e == nil {
+			return res, nil
+		}
+		if err == ErrNotFound {
+			err = e
+		}
+	}
+	return nil, err
+}
+
+// fourthCorners estimates where the centre of the missing bottom right finder
+// pattern would be: where the finder patterns put it as if the symbol were
+// seen straight on, then corrected for perspective from their module sizes
+func fourthCorners(t triple) []point {
+	square := point{t.tr.x + t.bl.x - t.tl.x, t.tr.y + t.bl.y - t.tl.y}
+	// a projective transform divides the affine position by a weight w that
+	// varies linearly across the symbol, and the module size goes as 1/w
+	wtl, wtr, wbl := 1/t.tl.module, 1/t.tr.module, 1/t.bl.module
+	w := wtr + wbl - wtl
+	if w <= 0 {
+		return []point{square}
+	}
+	return []point{square, {
+		(wtr*t.tr.x + wbl*t.bl.x - wtl*t.tl.x) / w,
+		(wtr*t.tr.y + wbl*t.bl.y - wtl*t.tl.y) / w,
+	}}
+}
+
+// decodeFrom decodes the symbol whose finder patterns are t and whose bottom
+// right finder pattern would be centred on at, refined by its alignment pattern
+func (b *bitmap) decodeFrom(t triple, size int, module float64, at point) (*Result, error) {
+	far := float64(size) - 3.5
+	corner := point{far, far}
+	transform := func(p point) transform {
+		return quadToQuad(
+			[4]point{{3.5, 3.5}, {far, 3.5}, corner, {3.5, far}},
+			[4]point{t.tl.point, t.tr.point, p, t.bl.point})
+	}
+	if size > 21 {
+		// the alignment pattern nearest to the bottom right is 3 modules closer to the top left
+		in := float64(size) - 6.5
+		est := transform(at).apply(in, in)
+		if p, ok := b.findAlignment(est, module); ok {
+			corner, at = point{in, in}, p
+		}
+	}
+	res, err := b.sampleDecode(transform(at), size)
+	if err == nil {
+		return res, nil
+	}
+	// Perspective moves the fourth corner away from the estimate, and a
+	// version 1 symbol has no alignment pattern to find it
+	for _, d := range cornerOffsets {
+		if res, e := b.sampleDecode(transform(point{at.x + d.x*module, at.y + d.y*module}), size); e == nil {
+			return res, nil
+		}
+	}
+	return nil, err
+}
+
+// cornerOffsets are the offsets in modules around the estimated fourth corner
+// tried by decodeSize, nearest first
+var cornerOffsets = func() []point {
+	var offsets []point
+	for y := -3.0; y <= 3; y += 0.5 {
+		for x := -3.0; x <= 3; x += 0.5 {
+			if x != 0 || y != 0 {
+				offsets = append(offsets, point{x, y})
+			}
+		}
+	}
+	sort.SliceStable(offsets, func(i, j int) bool {
+		return math.Hypot(offsets[i].x, offsets[i].y) < math.Hypot(offsets[j].x, offsets[j].y)
+	})
+	return offsets
+}()
+
+// sampleDecode samples the symbol through tr and decodes it, or its mirror image
+func (b *bitmap) sampleDecode(tr transform, size int) (*Result, error) {
+	grid, ok := b.sample(tr, size)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	res, err := decodeGrid(grid, size)
+	if err == nil {
+		return res, nil
+	}
+	if res, e := decodeGrid(transpose(grid, size), size); e == nil {
+		res.Mirrored = true
+		return res, nil
+	}
+	return nil, err
+}
+
+func transpose(grid []bool, size int) []bool {
+	t := make([]bool, len(grid))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			t[x*size+y] = grid[y*size+x]
+		}
+	}
+	return t
+}
+
+// readFormat returns the level and mask of the closest valid format information
+// to either copy, allowing up to 3 wrong bits
+func readFormat(grid []bool, size int) (Level, int, bool) {
+	at := func(x, y int) int {
+		if grid[y*size+x] {
+			return 1
+		}
+		return 0
+	}
+	var a, b int
+	for i := 0; i <= 5; i++ {
+		a |= at(8, i) << uint(i)
+	}
+	a |= at(8, 7)<<6 | at(8, 8)<<7 | at(7, 8)<<8
+	for i := 9; i < 15; i++ {
+		a |= at(14-i, 8) << uint(i)
+	}
+	for i := 0; i < 8; i++ {
+		b |= at(size-1-i, 8) << uint(i)
+	}
+	for i := 8; i < 15; i++ {
+		b |= at(8, size-15+i) << uint(i)
+	}
+	best, bestLevel, bestMask := 4, Low, 0
+	for l := Low; l <= High; l++ {
+		for mask := 0; mask < 8; mask++ {
+			f := formatInfo(l, mask)
+			for _, read := range []int{a, b} {
+				if d := bits.OnesCount(uint(f ^ read)); d < best {
+					best, bestLevel, bestMask = d, l, mask
+				}
+			}
+		}
+	}
+	return bestLevel, bestMask, best <= 3
+}
+
+// readVersion returns the version of the closest valid version information, allowing up to 3 wrong bits
+func readVersion(grid []bool, size int) (int, bool) {
+	var a, b int
+	for i := 0; i < 18; i++ {
+		x, y := size-11+i%3, i/3
+		if grid[y*size+x] {
+			a |= 1 << uint(i)
+		}
+		if grid[x*size+y] {
+			b |= 1 << uint(i)
+		}
+	}
+	best, version := 4, 0
+	for v := 7; v <= 40; v++ {
+		for _, read := range []int{a, b} {
+			if d := bits.OnesCount(uint(versionInfo(v) ^ read)); d < best {
+				best, version = d, v
+			}
+		}
+	}
+	return version, best <= 3
+}
+
+// decodeGrid reads the modules of a symbol, size modules wide
+func decodeGrid(grid []bool, size int) (*Result, error) {
+	version := (size - 17) / 4
+	if version >= 7 {
+		v, ok := readVersion(grid, size)
+		if !ok || v != version {
+			return nil, ErrFormat
+		}
+	}
+	level, mask, ok := readFormat(grid, size)
+	if !ok {
+		return nil, ErrFormat
+	}
+	c := newCode(version, level)
+	codewords := make([]byte, rawModules(version)/8)
+	i := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if c.isFunction[y*size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				if grid[y*size+x] != maskBit(mask, x, y) {
+					codewords[i>>3] |= 0x80 >> uint(i&7)
+				}
+				i++
+			}
+		}
+	}
+	data, corrected, err := c.deinterleave(codewords)
+	if err != nil {
+		return nil, err
+	}
+	payload, eci, err := parseSegments(data, version)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{Payload: payload, Version: version, Level: level, Mask: mask, Corrected: corrected, ECI: eci}, nil
+}
+
+// deinterleave splits the codewords into blocks, corrects them and returns the data codewords
+func (c *Code) deinterleave(codewords []byte) ([]byte, int, error) {
+	blocks := eccBlocks[c.Level][c.Version]
+	ecc := eccPerBlock[c.Level][c.Version]
+	raw := len(codewords)
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	list := make([][]byte, blocks)
+	dataLen := make([]int, blocks)
+	for i := range list {
+		dataLen[i] = shortLen - ecc
+		if i >= short {
+			dataLen[i]++
+		}
+	}
+	k := 0
+	for i := 0; i <= shortLen-ecc; i++ {
+		for j := range list {
+			if i < dataLen[j] {
+				list[j] = append(list[j], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < ecc; i++ {
+		for j := range list {
+			list[j] = append(list[j], codewords[k])
+			k++
+		}
+	}
+	var data []byte
+	corrected := 0
+	for j, block := range list {
+		n, err := rsDecode(block, ecc)
+		if err != nil {
+			return nil, 0, err
+		}
+		corrected += n
+		data = append(data, block[:dataLen[j]]...)
+	}
+	return data, corrected, nil
+}
+
+// bitReader reads bits most significant first
+type bitReader struct {
+	data []byte
+	n    int
+}
+
+func (r *bitReader) left() int {
+	return len(r.data)*8 - r.n
+}
+
+func (r *bitReader) read(bits int) int {
+	v := 0
+	for i := 0; i < bits; i++ {
+		v = v<<1 | int(r.data[r.n/8]>>uint(7-r.n%8)&1)
+		r.n++
+	}
+	return v
+}
+
+const alphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// parseSegments decodes the numeric, alphanumeric, byte and kanji segments of data
+func parseSegments(data []byte, version int) ([]byte, int, error) {
+	r := &bitReader{data: data}
+	var out []byte
+	eci := -1
+	group := 0
+	if version >= 10 {
+		group = 1
+	}
+	if version >= 27 {
+		group = 2
+	}
+	for r.left() >= 4 {
+		mode := r.read(4)
+		var count int
+		need := func(bits int) bool {
+			if r.left() < bits {
+				return false
+			}
+			count = r.read(bits)
+			return true
+		}
+		switch mode {
+		case 0x0: // terminator
+			return out, eci, nil
+		case 0x1: // numeric
+			if !need([]int{10, 12, 14}[group]) {
+				return nil, eci, ErrData
+			}
+			for ; count >= 3; count -= 3 {
+				if r.left() < 10 {
+					return nil, eci, ErrData
+				}
+				out = append(out, []byte(fmt.Sprintf("%03d", r.read(10)))...)
+			}
+			if count > 0 {
+				bits := []int{0, 4, 7}[count]
+				if r.left() < bits {
+					return nil, eci, ErrData
+				}
+				out = append(out, []byte(fmt.Sprintf("%0*d", count, r.read(bits)))...)
+			}
+		case 0x2: // alphanumeric
+			if !need([]int{9, 11, 13}[group]) {
+				return nil, eci, ErrData
+			}
+			for ; count >= 2; count -= 2 {
+				if r.left() < 11 {
+					return nil, eci, ErrData
+				}
+				v := r.read(11)
+				if v >= 45*45 {
+					return nil, eci, ErrData
+				}
+				out = append(out, alphanumeric[v/45], alphanumeric[v%45])
+			}
+			if count == 1 {
+				if r.left() < 6 {
+					return nil, eci, ErrData
+				}
+				v := r.read(6)
+				if v >= 45 {
+					return nil, eci, ErrData
+				}
+				out = append(out, alphanumeric[v])
+			}
+		case 0x4: // byte
+			if !need([]int{8, 16, 16}[group]) || r.left() < count*8 {
+				return nil, eci, ErrData
+			}
+			for ; count > 0; count-- {
+				out = append(out, byte(r.read(8)))
+			}
+		case 0x8: // kanji, written as Shift JIS
+			if !need([]int{8, 10, 12}[group]) || r.left() < count*13 {
+				return nil, eci, ErrData
+			}
+			for ; count > 0; count-- {
+				v := r.read(13)
+				v = v/0xC0<<8 | v%0xC0
+				if v < 0x1F00 {
+					v += 0x8140
+				} else {
+					v += 0xC140
+				}
+				out = append(out, byte(v>>8), byte(v))
+			}
+		case 0x7: // ECI designator
+			if r.left() < 8 {
+				return nil, eci, ErrData
+			}
+			first := r.read(8)
+			switch {
+			case first&0x80 == 0:
+				eci = first
+			case first&0xC0 == 0x80 && r.left() >= 8:
+				eci = (first&0x3F)<<8 | r.read(8)
+			case first&0xE0 == 0xC0 && r.left() >= 16:
+				eci = (first&0x1F)<<16 | r.read(16)
+			default:
+				return nil, eci, ErrData
+			}
+		case 0x3: // structured append: symbol position and parity
+			if r.left() < 16 {
+				return nil, eci, ErrData
+			}
+			r.read(16)
+		case 0x5: // FNC1 in first position
+		case 0x9: // FNC1 in second position: application indicator
+			if r.left() < 8 {
+				return nil, eci, ErrData
+			}
+			r.read(8)
+		default:
+			return nil, eci, ErrData
+		}
+	}
+	return out, eci, nil
+}
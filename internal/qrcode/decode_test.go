@@ -0,0 +1,180 @@
+package qrcode
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	fixturePayload = "00020101021229370016A000000677010111011300668123456785204581453037645406100.505802TH5904SHOP6007BANGKOK62070503ABC6304FF19"
+	fixtureShort   = "HELLO"
+)
+
+// warp maps img through the homography h, which takes the pixels of img to
+// those of a side x side grey image, averaging 4 samples per pixel
+func warp(img image.Image, h transform, side int) *image.Gray {
+	inv := h.adjoint()
+	b := img.Bounds()
+	dst := image.NewGray(image.Rect(0, 0, side, side))
+	for y := 0; y < side; y++ {
+		for x := 0; x < side; x++ {
+			sum := 0
+			for _, d := range [4]point{{0.25, 0.25}, {0.75, 0.25}, {0.25, 0.75}, {0.75, 0.75}} {
+				p := inv.apply(float64(x)+d.x, float64(y)+d.y)
+				sx, sy := int(math.Floor(p.x)), int(math.Floor(p.y))
+				v := uint8(255)
+				if image.Pt(sx, sy).In(b) {
+					v = color.GrayModel.Convert(img.At(sx, sy)).(color.Gray).Y
+				}
+				sum += int(v)
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(sum / 4)
+		}
+	}
+	return dst
+}
+
+// view renders c seen at angle degrees, with the near side of the symbol ratio
+// times as long as the far one along the direction (dx, dy)
+func view(c *Code, module int, angle, ratio, dx, dy float64) *image.Gray {
+	img := c.Image(module, DefaultQuietZone)
+	side := float64(img.Bounds().Dx())
+	p := 2 * (ratio - 1) / ((ratio + 1) * side)
+	n := math.Hypot(dx, dy)
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	h := transform{1, 0, side, 0, 1, side, 0, 0, 1}.
+		times(transform{cos, -sin, 0, sin, cos, 0, 0, 0, 1}).
+		times(transform{1, 0, 0, 0, 1, 0, p * dx / n, p * dy / n, 1}).
+		times(transform{1, 0, -side / 2, 0, 1, -side / 2, 0, 0, 1})
+	return warp(img, h, int(2*side))
+}
+
+// decodeFixtures are written to testdata by go test -update and read back by TestDecodeFixtures
+var decodeFixtures = []struct {
+	name     string // file in testdata
+	payload  string
+	level    Level
+	image    func(c *Code) image.Image
+	mirrored bool
+	inverted bool
+}{
+	{"decode-rotated-30.png", fixturePayload, Medium, func(c *Code) image.Image { return view(c, 4, 30, 1, 1, 0) }, false, false},
+	{"decode-rotated-200.png", fixturePayload, Medium, func(c *Code) image.Image { return view(c, 4, 200, 1, 1, 0) }, false, false},
+	{"decode-perspective-x.png", fixturePayload, Medium, func(c *Code) image.Image { return view(c, 5, 0, 1.3, 1, 0) }, false, false},
+	{"decode-perspective-xy.png", fixturePayload, Medium, func(c *Code) image.Image { return view(c, 5, 10, 1.3, -1, 1) }, false, false},
+	{"decode-perspective-v1.png", fixtureShort, Quartile, func(c *Code) image.Image { return view(c, 5, -20, 1.3, 0, 1) }, false, false},
+	{"decode-no-quiet-zone.png", fixturePayload, Medium, func(c *Code) image.Image { return c.Image(3, 0) }, false, false},
+	{"decode-mirrored.png", fixturePayload, Low, func(c *Code) image.Image { return mirror(c.Image(3, DefaultQuietZone)) }, true, false},
+	{"decode-inverted.png", fixturePayload, Low, func(c *Code) image.Image { return invert(c.Image(3, DefaultQuietZone)) }, false, true},
+	{"decode-jpeg.jpg", fixturePayload, Medium, func(c *Code) image.Image { return view(c, 3, 15, 1.1, 1, 1) }, false, false},
+}
+
+func mirror(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.Set(b.Max.X-1-x+b.Min.X, y, img.At(x, y))
+		}
+	}
+	return dst
+}
+
+func invert(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			dst.SetGray(x, y, color.Gray{255 - color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y})
+		}
+	}
+	return dst
+}
+
+func TestDecodeFixtures(t *testing.T) {
+	for _, tt := range decodeFixtures {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.name)
+			if *update {
+				c, err := EncodeString(tt.payload, tt.level)
+				if err != nil {
+					t.Fatal(err)
+				}
+				var buf bytes.Buffer
+				if filepath.Ext(path) == ".jpg" {
+					err = jpeg.Encode(&buf, tt.image(c), &jpeg.Options{Quality: 40})
+				} else {
+					err = png.Encode(&buf, tt.image(c))
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			img, _, err := image.Decode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := Decode(img)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(res.Payload) != tt.payload || res.Level != tt.level || res.Mirrored != tt.mirrored || res.Inverted != tt.inverted {
+				t.Errorf("got %q level %s mirrored %v inverted %v", res.Payload, res.Level, res.Mirrored, res.Inverted)
+			}
+		})
+	}
+}
+
+func TestDecodeDamaged(t *testing.T) {
+	c, err := EncodeString(fixturePayload, Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// flip a 4x4 block of data modules between the alignment patterns of the version 7 symbol
+	for y := 28; y < 32; y++ {
+		for x := 28; x < 32; x++ {
+			c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+		}
+	}
+	res, err := Decode(c.Image(3, DefaultQuietZone))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(res.Payload) != fixturePayload || res.Corrected == 0 {
+		t.Errorf("got %q with %d corrections", res.Payload, res.Corrected)
+	}
+}
+
+func TestDecodeNotFound(t *testing.T) {
+	blank := image.NewGray(image.Rect(0, 0, 100, 100))
+	for i := range blank.Pix {
+		blank.Pix[i] = 255
+	}
+	if _, err := Decode(blank); !errors.Is(err, ErrNotFound) {
+		t.Errorf("blank image: got %v, want %v", err, ErrNotFound)
+	}
+	// too far from straight on: the near side twice as long as the far one
+	c, err := EncodeString(fixturePayload, Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Decode(view(c, 4, 0, 2, 1, 0)); err == nil {
+		t.Error("decoded a symbol outside of the supported perspective")
+	}
+}
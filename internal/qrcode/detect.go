@@ -0,0 +1,478 @@
+package qrcode
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sort"
+)
+
+// bitmap is a binarized image, true is dark
+type bitmap struct {
+	w, h int
+	bits []bool
+}
+
+func (b *bitmap) at(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.w && y < b.h && b.bits[y*b.w+x]
+}
+
+func (b *bitmap) inside(x, y int) bool {
+	return x >= 0 && y >= 0 && x < b.w && y < b.h
+}
+
+// luminance returns the grey level of every pixel of img, row by row
+func luminance(img image.Image) ([]uint8, int, int) {
+	r := img.Bounds()
+	w, h := r.Dx(), r.Dy()
+	lum := make([]uint8, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			lum[y*w+x] = color.GrayModel.Convert(img.At(r.Min.X+x, r.Min.Y+y)).(color.Gray).Y
+		}
+	}
+	return lum, w, h
+}
+
+// otsu returns the threshold that best separates the two classes of the histogram of lum
+func otsu(lum []uint8) int {
+	var hist [256]int
+	for _, v := range lum {
+		hist[v]++
+	}
+	total, sum := len(lum), 0
+	for i, n := range hist {
+		sum += i * n
+	}
+	best, threshold := -1.0, 128
+	sumB, weightB := 0, 0
+	for t := 0; t < 256; t++ {
+		weightB += hist[t]
+		if weightB == 0 {
+			continue
+		}
+		weightF := total - weightB
+		if weightF == 0 {
+			break
+		}
+		sumB += t * hist[t]
+		mB := float64(sumB) / float64(weightB)
+		mF := float64(sum-sumB) / float64(weightF)
+		between := float64(weightB) * float64(weightF) * (mB - mF) * (mB - mF)
+		if between > best {
+			best, threshold = between, t
+		}
+	}
+	return threshold
+}
+
+// binarize thresholds lum globally, or per 8x8 block against the mean of the
+// surrounding 5x5 blocks when local is set, which copes with uneven lighting
+func binarize(lum []uint8, w, h int, local, invert bool) *bitmap {
+	b := &bitmap{w: w, h: h, bits: make([]bool, w*h)}
+	global := otsu(lum)
+	threshold := func(x, y int) int { return global }
+	if local {
+		const block = 8
+		bw, bh := (w+block-1)/block, (h+block-1)/block
+		mean := make([]int, bw*bh)
+		spread := make([]int, bw*bh)
+		for by := 0; by < bh; by++ {
+			for bx := 0; bx < bw; bx++ {
+				sum, n, lo, hi := 0, 0, 255, 0
+				for y := by * block; y < (by+1)*block && y < h; y++ {
+					for x := bx * block; x < (bx+1)*block && x < w; x++ {
+						v := int(lum[y*w+x])
+						sum += v
+						n++
+						if v < lo {
+							lo = v
+						}
+						if v > hi {
+							hi = v
+						}
+					}
+				}
+				mean[by*bw+bx] = sum / n
+				spread[by*bw+bx] = hi - lo
+			}
+		}
+		thresholds := make([]int, bw*bh)
+		for by := 0; by < bh; by++ {
+			for bx := 0; bx < bw; bx++ {
+				sum, n, maxSpread := 0, 0, 0
+				for y := by - 2; y <= by+2; y++ {
+					for x := bx - 2; x <= bx+2; x++ {
+						if x < 0 || y < 0 || x >= bw || y >= bh {
+							continue
+						}
+						sum += mean[y*bw+x]
+						n++
+						if spread[y*bw+x] > maxSpread {
+							maxSpread = spread[y*bw+x]
+						}
+					}
+				}
+				thresholds[by*bw+bx] = sum / n
+				if maxSpread < 24 { // flat area, no edge to separate
+					thresholds[by*bw+bx] = global
+				}
+			}
+		}
+		threshold = func(x, y int) int { return thresholds[(y/block)*bw+x/block] }
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			b.bits[y*w+x] = (int(lum[y*w+x]) <= threshold(x, y)) != invert
+		}
+	}
+	return b
+}
+
+// point is a position in the image in pixels
+type point struct {
+	x, y float64
+}
+
+func distance(a, b point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+// finder is a candidate finder pattern centre
+type finder struct {
+	point
+	module float64 // estimated module size in pixels
+	count  int     // number of scan lines that found it
+}
+
+// patternRatio reports whether the runs of counts match 1:1:3:1:1
+func patternRatio(counts [5]int) bool {
+	total := 0
+	for _, c := range counts {
+		if c == 0 {
+			return false
+		}
+		total += c
+	}
+	if total < 7 {
+		return false
+	}
+	m := float64(total) / 7
+	v := m / 2
+	return math.Abs(m-float64(counts[0])) < v &&
+		math.Abs(m-float64(counts[1])) < v &&
+		math.Abs(3*m-float64(counts[2])) < 3*v &&
+		math.Abs(m-float64(counts[3])) < v &&
+		math.Abs(m-float64(counts[4])) < v
+}
+
+// crossCheck counts the 1:1:3:1:1 runs through x, y along dx, dy and returns
+// the centre along that axis and the total length of the pattern
+func (b *bitmap) crossCheck(x, y, dx, dy, max int) (float64, int, bool) {
+	var counts [5]int
+	walk := func(k, step, state int, dark bool) int {
+		for ; b.inside(x+dx*k, y+dy*k) && b.at(x+dx*k, y+dy*k) == dark && counts[state] <= max; k += step {
+			counts[state]++
+		}
+		return k
+	}
+	if !b.at(x, y) {
+		return 0, 0, false
+	}
+	k := walk(0, -1, 2, true)
+	k = walk(k, -1, 1, false)
+	walk(k, -1, 0, true)
+	k = walk(1, 1, 2, true)
+	k = walk(k, 1, 3, false)
+	end := walk(k, 1, 4, true)
+	if !patternRatio(counts) {
+		return 0, 0, false
+	}
+	total := counts[0] + counts[1] + counts[2] + counts[3] + counts[4]
+	centre := float64(end-counts[4]-counts[3]) - float64(counts[2])/2
+	pos := float64(x)
+	if dy != 0 {
+		pos = float64(y)
+	}
+	return pos + centre, total, true
+}
+
+// findFinders scans every row for 1:1:3:1:1 runs and keeps the centres confirmed
+// by a vertical and a horizontal cross check
+func findFinders(b *bitmap) []finder {
+	var found []finder
+	add := func(p point, module float64) {
+		for i := range found {
+			f := &found[i]
+			if math.Abs(f.x-p.x) <= f.module*2 && math.Abs(f.y-p.y) <= f.module*2 && math.Abs(f.module-module) <= math.Max(1, f.module/2) {
+				n := float64(f.count)
+				f.x = (f.x*n + p.x) / (n + 1)
+				f.y = (f.y*n + p.y) / (n + 1)
+				f.module = (f.module*n + module) / (n + 1)
+				f.count++
+				return
+			}
+		}
+		found = append(found, finder{point: p, module: module, count: 1})
+	}
+	check := func(counts [5]int, end, y int) {
+		if !patternRatio(counts) {
+			return
+		}
+		total := counts[0] + counts[1] + counts[2] + counts[3] + counts[4]
+		cx := float64(end-counts[4]-counts[3]) - float64(counts[2])/2
+		cy, vTotal, ok := b.crossCheck(int(cx), y, 0, 1, counts[2]*2)
+		if !ok || 5*abs(vTotal-total) >= 2*total {
+			return
+		}
+		cx2, hTotal, ok := b.crossCheck(int(cx), int(cy), 1, 0, counts[2]*2)
+		if !ok || 5*abs(hTotal-total) >= 2*total {
+			return
+		}
+		add(point{cx2, cy}, float64(hTotal+vTotal)/14)
+	}
+	for y := 0; y < b.h; y++ {
+		var counts [5]int
+		state := 0
+		for x := 0; x < b.w; x++ {
+			dark := b.at(x, y)
+			switch {
+			case dark && state%2 == 1:
+				state++
+				counts[state]++
+			case dark:
+				counts[state]++
+			case state == 0 && counts[0] == 0:
+				// leading light pixels
+			case state%2 == 1:
+				counts[state]++
+			case state == 4:
+				check(counts, x, y)
+				counts = [5]int{counts[2], counts[3], counts[4], 1, 0}
+				state = 3
+			default:
+				state++
+				counts[state]++
+			}
+		}
+		if state == 4 {
+			check(counts, b.w, y)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].count > found[j].count })
+	return found
+}
+
+// triple is three finder patterns ordered top left, top right, bottom left
+type triple struct {
+	tl, tr, bl finder
+	score      float64
+}
+
+// triples returns the combinations of finders that could be the corners of a
+// symbol, the most square first
+func triples(found []finder) []triple {
+	if len(found) > 12 {
+		found = found[:12]
+	}
+	var list []triple
+	for i := 0; i < len(found); i++ {
+		for j := i + 1; j < len(found); j++ {
+			for k := j + 1; k < len(found); k++ {
+				if t, ok := makeTriple(found[i], found[j], found[k]); ok {
+					list = append(list, t)
+				}
+			}
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool { return list[i].score < list[j].score })
+	return list
+}
+
+func makeTriple(a, b, c finder) (triple, bool) {
+	lo := math.Min(a.module, math.Min(b.module, c.module))
+	hi := math.Max(a.module, math.Max(b.module, c.module))
+	if hi > lo*1.6 {
+		return triple{}, false
+	}
+	// the top left is opposite the longest side
+	ab, ac, bc := distance(a.point, b.point), distance(a.point, c.point), distance(b.point, c.point)
+	switch {
+	case bc >= ab && bc >= ac:
+	case ac >= ab && ac >= bc:
+		a, b = b, a
+		ac, bc = bc, ac
+	default:
+		a, c = c, a
+		ab, bc = bc, ab
+	}
+	if math.Min(ab, ac) < 10*lo {
+		return triple{}, false
+	}
+	side := math.Abs(ab-ac) / math.Max(ab, ac)
+	diag := math.Abs(bc-math.Hypot(ab, ac)) / bc
+	if side > 0.3 || diag > 0.2 {
+		return triple{}, false
+	}
+	// image y grows downwards: top right is clockwise from bottom left
+	if (b.x-a.x)*(c.y-a.y)-(b.y-a.y)*(c.x-a.x) < 0 {
+		b, c = c, b
+	}
+	// finder patterns are crossed by many scan lines, look-alikes in the data by few
+	seen := a.count
+	if b.count < seen {
+		seen = b.count
+	}
+	if c.count < seen {
+		seen = c.count
+	}
+	return triple{tl: a, tr: b, bl: c, score: side + diag + (hi-lo)/lo + 1/float64(seen)}, true
+}
+
+// patternWidth measures the finder pattern centred on from along the line to
+// to, its dark centre, light ring and dark ring both ways are 7 modules wide
+func (b *bitmap) patternWidth(from, to point) (float64, bool) {
+	d := distance(from, to)
+	if d == 0 {
+		return 0, false
+	}
+	ux, uy := (to.x-from.x)/d, (to.y-from.y)/d
+	width := 0.0
+	for _, sign := range []float64{1, -1} {
+		state := 0 // dark centre, light ring, dark ring
+		t := 0.0
+		for ; state < 3; t += 0.5 {
+			x, y := int(math.Floor(from.x+sign*ux*t)), int(math.Floor(from.y+sign*uy*t))
+			if !b.inside(x, y) {
+				return 0, false
+			}
+			if b.at(x, y) != (state%2 == 0) {
+				state++
+			}
+		}
+		width += t - 0.5
+	}
+	return width, true
+}
+
+// moduleSize estimates the module size of the symbol from the width of its
+// finder patterns measured along the sides, which is exact for rotated symbols
+func (b *bitmap) moduleSize(t triple) float64 {
+	sum, n := 0.0, 0
+	for _, pair := range [][2]point{{t.tl.point, t.tr.point}, {t.tr.point, t.tl.point}, {t.tl.point, t.bl.point}, {t.bl.point, t.tl.point}} {
+		if w, ok := b.patternWidth(pair[0], pair[1]); ok {
+			sum += w
+			n++
+		}
+	}
+	if n == 0 {
+		return (t.tl.module + t.tr.module + t.bl.module) / 3
+	}
+	return sum / float64(n) / 7
+}
+
+// findAlignment looks for the alignment pattern within 8 modules of p. Perspective changes
+// the module size across the symbol, so sizes around module are tried.
+func (b *bitmap) findAlignment(p point, module float64) (point, bool) {
+	radius := int(module * 8)
+	best, bestScore := point{}, 0
+	for _, m := range []float64{module, module * 0.85, module * 1.15, module * 0.7, module * 1.3} {
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+				c := point{p.x + float64(dx), p.y + float64(dy)}
+				score := 0
+				for my := -2; my <= 2; my++ {
+					for mx := -2; mx <= 2; mx++ {
+						want := abs(mx) == 2 || abs(my) == 2 || mx == 0 && my == 0
+						x := int(math.Floor(c.x + float64(mx)*m))
+						y := int(math.Floor(c.y + float64(my)*m))
+						if b.inside(x, y) && b.at(x, y) == want {
+							score++
+						}
+					}
+				}
+				if score > bestScore || score == bestScore && distance(c, p) < distance(best, p) {
+					best, bestScore = c, score
+				}
+			}
+		}
+		if bestScore == 25 {
+			break
+		}
+	}
+	return best, bestScore >= 23
+}
+
+// transform is a perspective transform, a 3x3 matrix acting on column vectors (x, y, 1)
+type transform [9]float64
+
+// squareToQuad maps the unit square (0,0) (1,0) (1,1) (0,1) onto the quadrilateral q
+func squareToQuad(q [4]point) transform {
+	dx3 := q[0].x - q[1].x + q[2].x - q[3].x
+	dy3 := q[0].y - q[1].y + q[2].y - q[3].y
+	if dx3 == 0 && dy3 == 0 {
+		return transform{
+			q[1].x - q[0].x, q[2].x - q[1].x, q[0].x,
+			q[1].y - q[0].y, q[2].y - q[1].y, q[0].y,
+			0, 0, 1,
+		}
+	}
+	dx1, dx2 := q[1].x-q[2].x, q[3].x-q[2].x
+	dy1, dy2 := q[1].y-q[2].y, q[3].y-q[2].y
+	den := dx1*dy2 - dx2*dy1
+	a13 := (dx3*dy2 - dx2*dy3) / den
+	a23 := (dx1*dy3 - dx3*dy1) / den
+	return transform{
+		q[1].x - q[0].x + a13*q[1].x, q[3].x - q[0].x + a23*q[3].x, q[0].x,
+		q[1].y - q[0].y + a13*q[1].y, q[3].y - q[0].y + a23*q[3].y, q[0].y,
+		a13, a23, 1,
+	}
+}
+
+// adjoint is the inverse of t up to a scale factor, which a projective transform ignores
+func (t transform) adjoint() transform {
+	return transform{
+		t[4]*t[8] - t[5]*t[7], t[2]*t[7] - t[1]*t[8], t[1]*t[5] - t[2]*t[4],
+		t[5]*t[6] - t[3]*t[8], t[0]*t[8] - t[2]*t[6], t[2]*t[3] - t[0]*t[5],
+		t[3]*t[7] - t[4]*t[6], t[1]*t[6] - t[0]*t[7], t[0]*t[4] - t[1]*t[3],
+	}
+}
+
+func (t transform) times(o transform) transform {
+	var r transform
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			for k := 0; k < 3; k++ {
+				r[i*3+j] += t[i*3+k] * o[k*3+j]
+			}
+		}
+	}
+	return r
+}
+
+// quadToQuad maps the quadrilateral from onto to
+func quadToQuad(from, to [4]point) transform {
+	return squareToQuad(to).times(squareToQuad(from).adjoint())
+}
+
+func (t transform) apply(x, y float64) point {
+	w := t[6]*x + t[7]*y + t[8]
+	return point{(t[0]*x + t[1]*y + t[2]) / w, (t[3]*x + t[4]*y + t[5]) / w}
+}
+
+// sample reads a size x size grid of modules through t, which maps module
+// coordinates to pixels. It fails when a module falls outside the image.
+func (b *bitmap) sample(t transform, size int) ([]bool, bool) {
+	grid := make([]bool, size*size)
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			p := t.apply(float64(x)+0.5, float64(y)+0.5)
+			px, py := int(math.Floor(p.x)), int(math.Floor(p.y))
+			if !b.inside(px, py) {
+				return nil, false
+			}
+			grid[y*size+x] = b.at(px, py)
+		}
+	}
+	return grid, true
+}
@@ -0,0 +1,177 @@
+package qrcode
+
+// Arithmetic in GF(256) with the QR Code primitive polynomial x^8+x^4+x^3+x^2+1
+var (
+	gfExp [512]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = byte(i)
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11D
+		}
+	}
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+// rsGenerator returns the Reed-Solomon generator polynomial of the given degree,
+// highest coefficient first without the leading 1
+func rsGenerator(degree int) []byte {
+	g := make([]byte, degree)
+	g[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := 0; j < degree; j++ {
+			g[j] = gfMul(g[j], root)
+			if j+1 < degree {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return g
+}
+
+// rsEncode returns the error correction codewords of data
+func rsEncode(data []byte, degree int) []byte {
+	g := rsGenerator(degree)
+	ecc := make([]byte, degree)
+	for _, b := range data {
+		factor := b ^ ecc[0]
+		copy(ecc, ecc[1:])
+		ecc[degree-1] = 0
+		for i := range ecc {
+			ecc[i] ^= gfMul(g[i], factor)
+		}
+	}
+	return ecc
+}
+
+func gfInv(a byte) byte {
+	return gfExp[255-int(gfLog[a])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// gfPoly is a polynomial over GF(256), lowest degree coefficient first
+type gfPoly []byte
+
+func (p gfPoly) eval(x byte) byte {
+	var y byte
+	for i := len(p) - 1; i >= 0; i-- {
+		y = gfMul(y, x) ^ p[i]
+	}
+	return y
+}
+
+// rsDecode corrects codewords, data followed by degree error correction codewords,
+// in place and returns the number of codewords corrected
+func rsDecode(codewords []byte, degree int) (int, error) {
+	n := len(codewords)
+	// syndromes S_i = r(a^i), codewords[0] being the highest degree coefficient
+	synd := make(gfPoly, degree)
+	clean := true
+	for i := range synd {
+		var s byte
+		for _, c := range codewords {
+			s = gfMul(s, gfExp[i]) ^ c
+		}
+		synd[i] = s
+		clean = clean && s == 0
+	}
+	if clean {
+		return 0, nil
+	}
+
+	// Berlekamp-Massey: error locator polynomial
+	lambda, prev := gfPoly{1}, gfPoly{1}
+	l, m, b := 0, 1, byte(1)
+	for k := 0; k < degree; k++ {
+		d := synd[k]
+		for i := 1; i <= l && i < len(lambda); i++ {
+			d ^= gfMul(lambda[i], synd[k-i])
+		}
+		if d == 0 {
+			m++
+			continue
+		}
+		next := append(gfPoly(nil), lambda...)
+		for len(next) < len(prev)+m {
+			next = append(next, 0)
+		}
+		coef := gfDiv(d, b)
+		for i, p := range prev {
+			next[i+m] ^= gfMul(coef, p)
+		}
+		if 2*l <= k {
+			l, prev, b, m = k+1-l, lambda, d, 1
+		} else {
+			m++
+		}
+		lambda = next
+	}
+	if 2*l > degree {
+		return 0, ErrChecksum
+	}
+
+	// Chien search: the error at power j of the codeword is a root a^-j of lambda
+	var positions []int
+	for j := 0; j < n; j++ {
+		if lambda.eval(gfExp[(255-j%255)%255]) == 0 {
+			positions = append(positions, j)
+		}
+	}
+	if len(positions) != l {
+		return 0, ErrChecksum
+	}
+
+	// Forney: error values from omega = S * lambda mod x^degree
+	omega := make(gfPoly, degree)
+	for i := range omega {
+		for j := 0; j <= i && j < len(lambda); j++ {
+			omega[i] ^= gfMul(lambda[j], synd[i-j])
+		}
+	}
+	deriv := make(gfPoly, len(lambda))
+	for i := 1; i < len(lambda); i += 2 {
+		deriv[i-1] = lambda[i]
+	}
+	for _, j := range positions {
+		x := gfExp[j%255]
+		xInv := gfInv(x)
+		den := deriv.eval(xInv)
+		if den == 0 {
+			return 0, ErrChecksum
+		}
+		codewords[n-1-j] ^= gfMul(x, gfDiv(omega.eval(xInv), den))
+	}
+	for i := 0; i < degree; i++ {
+		var s byte
+		for _, c := range codewords {
+			s = gfMul(s, gfExp[i]) ^ c
+		}
+		if s != 0 {
+			return 0, ErrChecksum
+		}
+	}
+	return len(positions), nil
+}
@@ -0,0 +1,447 @@
+// Package qrcode encodes payloads into QR Code Model 2 symbols (ISO/IEC 18004)
+// and renders them as images, PNG or SVG without any external dependency.
+package qrcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Level is the error correction level of a symbol
+type Level int
+
+const (
+	Low      Level = iota // L, about 7% of the codewords can be restored
+	Medium                // M, about 15%
+	Quartile              // Q, about 25%
+	High                  // H, about 30%
+)
+
+func (l Level) String() string {
+	switch l {
+	case Low:
+		return "L"
+	case Medium:
+		return "M"
+	case Quartile:
+		return "Q"
+	case High:
+		return "H"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// formatBits are the 2 bits of the level written in the format information
+func (l Level) formatBits() int {
+	return [...]int{1, 0, 3, 2}[l]
+}
+
+// ErrTooLong is returned when the payload does not fit in a version 40 symbol
+var ErrTooLong = errors.New("qrcode: payload is too long")
+
+// Code is an encoded QR Code symbol
+type Code struct {
+	Version int // 1 to 40
+	Level   Level
+	Mask    int // 0 to 7
+	Size    int // modules per side, 4*Version+17
+
+	modules    []bool // dark modules, row by row
+	isFunction []bool // modules of the function patterns, used while encoding
+}
+
+// Black reports whether the module at column x and row y is dark.
+// Modules outside the symbol are light.
+func (c *Code) Black(x, y int) bool {
+	return x >= 0 && y >= 0 && x < c.Size && y < c.Size && c.modules[y*c.Size+x]
+}
+
+func (c *Code) set(x, y int, dark bool) {
+	c.modules[y*c.Size+x] = dark
+	c.isFunction[y*c.Size+x] = true
+}
+
+// Encode encodes data in byte mode into the smallest symbol of the given level.
+// The mask with the lowest penalty is chosen, so the same input always gives the same symbol.
+func Encode(data []byte, level Level) (*Code, error) {
+	if level < Low || level > High {
+		return nil, fmt.Errorf("qrcode: unknown error correction level %d", int(level))
+	}
+	version := 1
+	for ; version <= 40; version++ {
+		if bitsNeeded(len(data), version) <= dataCodewords(version, level)*8 {
+			break
+		}
+	}
+	if version > 40 {
+		return nil, ErrTooLong
+	}
+	c := newCode(version, level)
+	c.drawCodewords(c.interleave(c.dataBits(data)))
+
+	best, penalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		c.applyMask(mask)
+		c.drawFormat(mask)
+		if p := c.penalty(); penalty < 0 || p < penalty {
+			best, penalty = mask, p
+		}
+		c.applyMask(mask) // XOR again to undo
+	}
+	c.Mask = best
+	c.applyMask(best)
+	c.drawFormat(best)
+	c.isFunction = nil
+	return c, nil
+}
+
+// EncodeString encodes s in byte mode, see Encode
+func EncodeString(s string, level Level) (*Code, error) {
+	return Encode([]byte(s), level)
+}
+
+// bitsNeeded returns the length of a byte mode segment of n bytes
+func bitsNeeded(n, version int) int {
+	return 4 + countBits(version) + 8*n
+}
+
+// countBits is the length of the byte mode character count indicator
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+func newCode(version int, level Level) *Code {
+	size := version*4 + 17
+	c := &Code{
+		Version:    version,
+		Level:      level,
+		Size:       size,
+		modules:    make([]bool, size*size),
+		isFunction: make([]bool, size*size),
+	}
+	c.drawFunctionPatterns()
+	return c
+}
+
+// drawFunctionPatterns draws the finder, timing and alignment patterns and
+// reserves the format and version information areas
+func (c *Code) drawFunctionPatterns() {
+	for i := 0; i < c.Size; i++ {
+		c.set(6, i, i%2 == 0)
+		c.set(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(c.Size-4, 3)
+	c.drawFinder(3, c.Size-4)
+
+	pos := alignmentPositions(c.Version)
+	for i, x := range pos {
+		for j, y := range pos {
+			last := len(pos) - 1
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue // overlaps a finder pattern
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					c.set(x+dx, y+dy, abs(dx) == 2 || abs(dy) == 2 || dx == 0 && dy == 0)
+				}
+			}
+		}
+	}
+	c.drawFormat(0)
+	c.drawVersion()
+}
+
+// drawFinder draws a finder pattern centred on x, y with its separator
+func (c *Code) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || yy < 0 || xx >= c.Size || yy >= c.Size {
+				continue
+			}
+			d := abs(dx)
+			if abs(dy) > d {
+				d = abs(dy)
+			}
+			c.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// formatInfo returns the 15 bits BCH coded format information of level and mask
+func formatInfo(level Level, mask int) int {
+	data := level.formatBits()<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormat writes both copies of the format information and the dark module
+func (c *Code) drawFormat(mask int) {
+	bits := formatInfo(c.Level, mask)
+	bit := func(i int) bool { return bits>>uint(i)&1 != 0 }
+	for i := 0; i <= 5; i++ {
+		c.set(8, i, bit(i))
+	}
+	c.set(8, 7, bit(6))
+	c.set(8, 8, bit(7))
+	c.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		c.set(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.set(8, c.Size-15+i, bit(i))
+	}
+	c.set(8, c.Size-8, true)
+}
+
+// versionInfo returns the 18 bits BCH coded version information
+func versionInfo(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return version<<12 | rem
+}
+
+// drawVersion writes both copies of the version information of versions 7 and above
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	bits := versionInfo(c.Version)
+	for i := 0; i < 18; i++ {
+		dark := bits>>uint(i)&1 != 0
+		a, b := c.Size-11+i%3, i/3
+		c.set(a, b, dark)
+		c.set(b, a, dark)
+	}
+}
+
+// dataBits returns the data codewords: a byte mode segment, the terminator and padding
+func (c *Code) dataBits(data []byte) []byte {
+	capacity := dataCodewords(c.Version, c.Level) * 8
+	var w bitWriter
+	w.write(0x4, 4) // byte mode
+	w.write(len(data), countBits(c.Version))
+	for _, b := range data {
+		w.write(int(b), 8)
+	}
+	if t := capacity - w.n; t < 4 {
+		w.write(0, t)
+	} else {
+		w.write(0, 4)
+	}
+	w.write(0, (8-w.n%8)%8)
+	for pad := 0xEC; w.n < capacity; pad ^= 0xEC ^ 0x11 {
+		w.write(pad, 8)
+	}
+	return w.bytes
+}
+
+// interleave splits data into blocks, appends their error correction
+// codewords and interleaves them
+func (c *Code) interleave(data []byte) []byte {
+	blocks := eccBlocks[c.Level][c.Version]
+	ecc := eccPerBlock[c.Level][c.Version]
+	raw := rawModules(c.Version) / 8
+	short := blocks - raw%blocks
+	shortLen := raw / blocks
+
+	var dataBlocks, eccBlockList [][]byte
+	for i, k := 0, 0; i < blocks; i++ {
+		n := shortLen - ecc
+		if i >= short {
+			n++
+		}
+		dataBlocks = append(dataBlocks, data[k:k+n])
+		eccBlockList = append(eccBlockList, rsEncode(data[k:k+n], ecc))
+		k += n
+	}
+	result := make([]byte, 0, raw)
+	for i := 0; i <= shortLen-ecc; i++ {
+		for _, b := range dataBlocks {
+			if i < len(b) {
+				result = append(result, b[i])
+			}
+		}
+	}
+	for i := 0; i < ecc; i++ {
+		for _, b := range eccBlockList {
+			result = append(result, b[i])
+		}
+	}
+	return result
+}
+
+// drawCodewords places the codewords in the zigzag order of two modules wide columns
+func (c *Code) drawCodewords(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert // upwards
+				}
+				if c.isFunction[y*c.Size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.modules[y*c.Size+x] = codewords[i>>3]>>uint(7-i&7)&1 != 0
+				i++
+			}
+		}
+	}
+}
+
+// maskBit reports whether the module at x, y is flipped by mask
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// applyMask flips the data modules selected by mask, applying it twice undoes it
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.isFunction[y*c.Size+x] && maskBit(mask, x, y) {
+				c.modules[y*c.Size+x] = !c.modules[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol with the four rules of ISO/IEC 18004:2015 7.8.3.1, lower is better
+func (c *Code) penalty() int {
+	p := 0
+	for i := 0; i < c.Size; i++ {
+		p += c.linePenalty(func(j int) bool { return c.modules[i*c.Size+j] })
+		p += c.linePenalty(func(j int) bool { return c.modules[j*c.Size+i] })
+	}
+	// Rule 2: 3 points for each 2x2 block of one colour, overlapping blocks included
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			d := c.Black(x, y)
+			if d == c.Black(x+1, y) && d == c.Black(x, y+1) && d == c.Black(x+1, y+1) {
+				p += 3
+			}
+		}
+	}
+	dark := 0
+	for _, m := range c.modules {
+		if m {
+			dark++
+		}
+	}
+	return p + balancePenalty(dark, c.Size*c.Size)
+}
+
+// balancePenalty is rule 4: 10 points for each 5% step, or part of one, the
+// dark proportion is outside of 45% to 55%
+func balancePenalty(dark, total int) int {
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	if k < 0 {
+		k = 0
+	}
+	return 10 * k
+}
+
+// linePenalty scores a row or a column with rules 1 and 3: 3 points for a run
+// of 5 modules of one colour and 1 more for each further module, and 40 points
+// for each side of a dark:light:dark:light:dark 1:1:3:1:1 pattern of unit n
+// with a light run of 4n there and at least n on the other side. The area
+// outside the symbol is light, so a pattern may touch the edge.
+func (c *Code) linePenalty(at func(int) bool) int {
+	p, run := 0, 1
+	for j := 1; j <= c.Size; j++ {
+		if j < c.Size && at(j) == at(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			p += 3 + run - 5
+		}
+		run = 1
+	}
+	// Run lengths of the line, light and dark in turn, starting and ending light
+	runs := []int{c.Size}
+	for j := 0; j < c.Size; j++ {
+		if at(j) == (len(runs)%2 == 0) {
+			runs[len(runs)-1]++
+		} else {
+			runs = append(runs, 1)
+		}
+	}
+	if len(runs)%2 == 0 {
+		runs = append(runs, c.Size)
+	} else {
+		runs[len(runs)-1] += c.Size
+	}
+	for i := 3; i+3 < len(runs); i += 2 {
+		n := runs[i-1]
+		if runs[i-2] != n || runs[i] != 3*n || runs[i+1] != n || runs[i+2] != n {
+			continue
+		}
+		if runs[i-3] >= 4*n && runs[i+3] >= n {
+			p += 40
+		}
+		if runs[i+3] >= 4*n && runs[i-3] >= n {
+			p += 40
+		}
+	}
+	return p
+}
+
+// bitWriter appends bits most significant first
+type bitWriter struct {
+	bytes []byte
+	n     int
+}
+
+func (w *bitWriter) write(v, bits int) {
+	for i := bits - 1; i >= 0; i-- {
+		if w.n%8 == 0 {
+			w.bytes = append(w.bytes, 0)
+		}
+		if v>>uint(i)&1 != 0 {
+			w.bytes[w.n/8] |= 0x80 >> uint(w.n%8)
+		}
+		w.n++
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,197 @@
+package qrcode
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenCodes are encoded and compared byte for byte with testdata/<name>.png and .svg
+var goldenCodes = []struct {
+	name    string
+	payload string
+	level   Level
+}{
+	{"promptpay", "00020101021229370016A000000677010111011300668123456785204581453037645406100.505802TH5904SHOP6007BANGKOK62070503ABC6304FF19", Medium},
+	{"hello-low", "HELLO WORLD", Low},
+	{"hello-high", "HELLO WORLD", High},
+	{"version8", strings.Repeat("0123456789", 14), Medium},
+}
+
+func TestGolden(t *testing.T) {
+	for _, tt := range goldenCodes {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := EncodeString(tt.payload, tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			png, err := c.PNG(4, DefaultQuietZone)
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, tt.name+".png", png)
+			golden(t, tt.name+".svg", c.SVG(1, DefaultQuietZone))
+		})
+	}
+}
+
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from the golden file, run go test -update if the change is intended", path)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for level := Low; level <= High; level++ {
+		for _, n := range []int{1, 17, 60, 150, 400, 1200} {
+			payload := make([]byte, n)
+			for i := range payload {
+				payload[i] = byte(i*7 + n)
+			}
+			c, err := Encode(payload, level)
+			if err == ErrTooLong {
+				continue
+			}
+			if err != nil {
+				t.Fatalf("%s/%d: %v", level, n, err)
+			}
+			res, err := Decode(c.Image(3, DefaultQuietZone))
+			if err != nil {
+				t.Errorf("%s/%d version %d mask %d: %v", level, n, c.Version, c.Mask, err)
+				continue
+			}
+			if !bytes.Equal(res.Payload, payload) || res.Version != c.Version || res.Level != level || res.Mask != c.Mask || res.Corrected != 0 {
+				t.Errorf("%s/%d: decoded version %d %s mask %d with %d corrections, want version %d mask %d",
+					level, n, res.Version, res.Level, res.Mask, res.Corrected, c.Version, c.Mask)
+			}
+		}
+	}
+}
+
+// encodeMask encodes data like Encode but with the given mask
+func encodeMask(data []byte, level Level, mask int) *Code {
+	version := 1
+	for bitsNeeded(len(data), version) > dataCodewords(version, level)*8 {
+		version++
+	}
+	c := newCode(version, level)
+	c.drawCodewords(c.interleave(c.dataBits(data)))
+	c.applyMask(mask)
+	c.drawFormat(mask)
+	c.Mask = mask
+	return c
+}
+
+func TestMasks(t *testing.T) {
+	payload := []byte("00020101021129370016A0000006770101110213123456789012153037645802TH6304C3BF")
+	for mask := 0; mask < 8; mask++ {
+		c := encodeMask(payload, Quartile, mask)
+		res, err := decodeGrid(c.modules, c.Size)
+		if err != nil {
+			t.Fatalf("mask %d: %v", mask, err)
+		}
+		if res.Mask != mask || !bytes.Equal(res.Payload, payload) {
+			t.Errorf("mask %d: decoded mask %d payload %q", mask, res.Mask, res.Payload)
+		}
+	}
+}
+
+// line makes a one row symbol from s, # is dark
+func line(s string) (*Code, func(int) bool) {
+	c := &Code{Size: len(s)}
+	return c, func(j int) bool { return s[j] == '#' }
+}
+
+func TestLinePenalty(t *testing.T) {
+	tests := []struct {
+		line string
+		want int
+	}{
+		{"#.#.#.#.#.", 0},
+		{"#####", 3},
+		{"......", 4},
+		{"####.#####", 3},
+		{"#.###.#", 80},            // light on both sides past the edges
+		{"#.###.#....#", 80},       // 4 light modules after it
+		{"#.###.#...#", 40},        // 3 light modules after it, the edge before it
+		{"#..#.###.#..#", 0},       // 2 light modules on both sides
+		{"##.###.#....#", 0},       // its first dark run is 2 modules
+		{"#.##.#.#....#", 0},       // not 1:1:3:1:1
+		{"##..######..##", 80 + 4}, // twice the unit, and a run of 6
+		{"##.##..######..##", 4},   // 1 light module before a unit of 2
+		{"........#.###.#........", 80 + 2*(3+3)},
+	}
+	for _, tt := range tests {
+		c, at := line(tt.line)
+		if got := c.linePenalty(at); got != tt.want {
+			t.Errorf("linePenalty(%s) = %d, want %d", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestBalancePenalty(t *testing.T) {
+	tests := []struct {
+		dark, total, want int
+	}{
+		{50, 100, 0},
+		{45, 100, 0},
+		{55, 100, 0},
+		{56, 100, 10},
+		{60, 100, 10},
+		{61, 100, 20},
+		{30, 100, 30},
+		{100, 100, 90},
+		{0, 100, 90},
+	}
+	for _, tt := range tests {
+		if got := balancePenalty(tt.dark, tt.total); got != tt.want {
+			t.Errorf("balancePenalty(%d, %d) = %d, want %d", tt.dark, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestPenalty(t *testing.T) {
+	square := func(size int, dark func(x, y int) bool) *Code {
+		c := &Code{Size: size, modules: make([]bool, size*size)}
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				c.modules[y*size+x] = dark(x, y)
+			}
+		}
+		return c
+	}
+	tests := []struct {
+		name string
+		code *Code
+		want int
+	}{
+		{"checkerboard", square(21, func(x, y int) bool { return (x+y)%2 == 0 }), 0},
+		// 42 lines of 21 modules, 20x20 2x2 blocks and 100% dark
+		{"dark", square(21, func(x, y int) bool { return true }), 42*(3+16) + 3*20*20 + 90},
+		// every row is a finder-like pattern with light past both edges, the
+		// columns are runs of 7 and 35 of the 49 modules are dark
+		{"finder rows", square(7, func(x, y int) bool { return x != 1 && x != 5 }), 7*80 + 7*(3+2) + 3*2*6 + 40},
+	}
+	for _, tt := range tests {
+		if got := tt.code.penalty(); got != tt.want {
+			t.Errorf("%s: penalty() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package qrcode
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// DefaultQuietZone is the margin in modules required around a symbol by ISO/IEC 18004
+const DefaultQuietZone = 4
+
+var palette = color.Palette{color.White, color.Black}
+
+// Image renders the symbol with moduleSize pixels per module and a margin of
+// quietZone modules on every side. moduleSize below 1 is 1 and quietZone below 0 is 0.
+func (c *Code) Image(moduleSize, quietZone int) image.Image {
+	moduleSize, quietZone = clamp(moduleSize, quietZone)
+	side := (c.Size + 2*quietZone) * moduleSize
+	img := image.NewPaletted(image.Rect(0, 0, side, side), palette)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			px, py := (x+quietZone)*moduleSize, (y+quietZone)*moduleSize
+			for dy := 0; dy < moduleSize; dy++ {
+				row := img.Pix[(py+dy)*img.Stride:]
+				for dx := 0; dx < moduleSize; dx++ {
+					row[px+dx] = 1
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG renders the symbol as a 1 bit per pixel PNG, see Image
+func (c *Code) PNG(moduleSize, quietZone int) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(&buf, c.Image(moduleSize, quietZone)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG renders the symbol as an SVG document with a single path of the dark
+// modules, moduleSize is the size of a module in user units, see Image
+func (c *Code) SVG(moduleSize, quietZone int) []byte {
+	moduleSize, quietZone = clamp(moduleSize, quietZone)
+	side := (c.Size + 2*quietZone) * moduleSize
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<?xml version="1.0" encoding="UTF-8"?>`+"\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" version="1.1" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n", side, side, c.Size+2*quietZone, c.Size+2*quietZone)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#FFFFFF"/>`+"\n")
+	buf.WriteString(`<path fill="#000000" d="`)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.Black(x, y) {
+				continue
+			}
+			run := 1
+			for c.Black(x+run, y) {
+				run++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", x+quietZone, y+quietZone, run, run)
+			x += run
+		}
+	}
+	buf.WriteString(`"/>` + "\n</svg>\n")
+	return buf.Bytes()
+}
+
+func clamp(moduleSize, quietZone int) (int, int) {
+	if moduleSize < 1 {
+		moduleSize = 1
+	}
+	if quietZone < 0 {
+		quietZone = 0
+	}
+	return moduleSize, quietZone
+}
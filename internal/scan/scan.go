@@ -0,0 +1,68 @@
+// Package scan reads payment QR codes from screenshots and photos of
+// merchant QRs and bank slips.
+package scan
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG for image.Decode
+	_ "image/png"  // register PNG for image.Decode
+	"io"
+
+	"thaiqr-go/internal/qr"
+	"thaiqr-go/internal/qrcode"
+)
+
+// Result is a payment QR read from an image
+type Result struct {
+	Payload  string
+	QR       *qr.QR     // merchant-presented QR, nil for a slip
+	Slip     *qr.SlipQR // slip verification QR, nil for a merchant QR
+	Symbol   *qrcode.Result
+	Warnings []string // non-fatal problems found while reading and validating
+}
+
+// Read decodes a PNG or JPEG image and reads the payment QR it shows
+func Read(r io.Reader) (*Result, error) {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("scan: %w", err)
+	}
+	return Image(img)
+}
+
+// Image finds the QR symbol in img and decodes its payload as a slip
+// verification QR or, otherwise, as a Thai merchant QR with DecodeQRVisa
+func Image(img image.Image) (*Result, error) {
+	sym, err := qrcode.Decode(img)
+	if err != nil {
+		return nil, err
+	}
+	res := &Result{Payload: string(sym.Payload), Symbol: sym}
+	if sym.Corrected > 0 {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("%d damaged codewords were restored by error correction", sym.Corrected))
+	}
+	if sym.Mirrored {
+		res.Warnings = append(res.Warnings, "the QR code is mirrored")
+	}
+	if sym.Inverted {
+		res.Warnings = append(res.Warnings, "the QR code is light on a dark background")
+	}
+
+	if qr.IsSlip(res.Payload) {
+		if res.Slip, err = qr.DecodeSlip(res.Payload); err != nil {
+			return nil, err
+		}
+		if res.Slip.BankName() == "" {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("unknown sending bank code %q", res.Slip.SendingBank))
+		}
+		return res, nil
+	}
+	if res.QR, err = qr.DecodeQRVisa(res.Payload); err != nil {
+		return nil, err
+	}
+	for _, i := range qr.ValidateString(res.Payload).Warnings() {
+		res.Warnings = append(res.Warnings, i.String())
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package scan
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"strings"
+	"testing"
+
+	"thaiqr-go/internal/qr"
+	"thaiqr-go/internal/qrcode"
+)
+
+const merchantPayload = "00020101021229370016A000000677010111011300668123456785204581453037645406100.505802TH5904SHOP6007BANGKOK62070503ABC6304FF19"
+
+func slipPayload(t *testing.T, bank string) string {
+	t.Helper()
+	s, err := qr.EncodeSlip(&qr.SlipQR{SendingBank: bank, TransRef: "016251103512345678"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func png(t *testing.T, payload string) []byte {
+	t.Helper()
+	c, err := qrcode.EncodeString(payload, qrcode.Medium)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := c.PNG(4, qrcode.DefaultQuietZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		slip    bool
+		warning string
+	}{
+		{"merchant", merchantPayload, false, ""},
+		{"slip", slipPayload(t, "014"), true, ""},
+		{"slip of an unknown bank", slipPayload(t, "999"), true, `unknown sending bank code "999"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Read(bytes.NewReader(png(t, tt.payload)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.Payload != tt.payload {
+				t.Errorf("Payload = %q, want %q", res.Payload, tt.payload)
+			}
+			if tt.slip && (res.Slip == nil || res.QR != nil) {
+				t.Errorf("got QR %v and Slip %v, want a slip", res.QR, res.Slip)
+			}
+			if !tt.slip && (res.QR == nil || res.Slip != nil) {
+				t.Errorf("got QR %v and Slip %v, want a merchant QR", res.QR, res.Slip)
+			}
+			if got := strings.Join(res.Warnings, "; "); got != tt.warning {
+				t.Errorf("Warnings = %q, want %q", got, tt.warning)
+			}
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	if _, err := Read(strings.NewReader("not an image")); err == nil || !strings.HasPrefix(err.Error(), "scan: ") {
+		t.Errorf("not an image: got %v", err)
+	}
+	blank := image.NewGray(image.Rect(0, 0, 64, 64))
+	for i := range blank.Pix {
+		blank.Pix[i] = 255
+	}
+	if _, err := Image(blank); !errors.Is(err, qrcode.ErrNotFound) {
+		t.Errorf("blank image: got %v, want %v", err, qrcode.ErrNotFound)
+	}
+	// a merchant QR with a broken CRC
+	bad := merchantPayload[:len(merchantPayload)-4] + "0000"
+	var pe *qr.ParseError
+	if _, err := Read(bytes.NewReader(png(t, bad))); !errors.As(err, &pe) || !errors.Is(err, qr.ErrCRCMismatch) {
+		t.Errorf("bad CRC: got %v, want %v", err, qr.ErrCRCMismatch)
+	}
+}
@@ -0,0 +1,89 @@
+package thaiqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"thaiqr-go/internal/qr"
+)
+
+var testBiller = Biller{TaxID: "0994000165501", Suffix: "00"}
+
+func TestBillPayment(t *testing.T) {
+	parsed, err := ParseBillerID("099400016550100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed != testBiller {
+		t.Fatalf("ParseBillerID = %+v, want %+v", parsed, testBiller)
+	}
+	withRef2 := testBiller
+	withRef2.RequireReference2 = true
+
+	tests := []struct {
+		name string
+		b    *BillPayment
+		err  error
+	}{
+		{"Reference 1 is required for a biller literal", NewBillPayment(testBiller), ErrMissingReference},
+		{"Reference 1 is required for a parsed biller", NewBillPayment(parsed), ErrMissingReference},
+		{"Reference 1", NewBillPayment(parsed).Reference1("REF1"), nil},
+		{"Reference 2 required", NewBillPayment(withRef2).Reference1("REF1"), ErrMissingReference},
+		{"Reference 2", NewBillPayment(withRef2).Reference1("REF1").Reference2("R2"), nil},
+		{"lower case reference", NewBillPayment(testBiller).Reference1("ref1"), ErrInvalidReference},
+		{"reference too long", NewBillPayment(testBiller).Reference1("123456789012345678901"), ErrInvalidReference},
+		{"bad biller", NewBillPayment(Biller{TaxID: "0994000165502", Suffix: "00"}).Reference1("REF1"), ErrInvalidBiller},
+		{"amount", NewBillPayment(testBiller).Reference1("REF1").Amount("5.00"), nil},
+		{"zero amount", NewBillPayment(testBiller).Reference1("REF1").Amount("0"), ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		s, err := tt.b.Build()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		q, err := qr.DecodeQRVisa(s)
+		if err != nil {
+			t.Errorf("%s: %s does not decode: %v", tt.name, s, err)
+			continue
+		}
+		if bp := q.Merchant.ID.PromptPayBillPayment; bp.BillerID != "099400016550100" || bp.Reference1 != "REF1" {
+			t.Errorf("%s: decoded %+v", tt.name, bp)
+		}
+		for _, issue := range qr.Validate(q).Errors() {
+			if strings.HasPrefix(issue.Tag, "30") {
+				t.Errorf("%s: %s: %v", tt.name, s, issue)
+			}
+		}
+	}
+}
+
+func TestBillPaymentPayload(t *testing.T) {
+	s, err := NewBillPayment(testBiller).Reference1("REF1").Reference2("R2").Amount("5.00").Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = "00020101021230530016A00000067701011201150994000165501000204REF10302R2530376454045.005802TH6304BBA4"
+	if s != want {
+		t.Errorf("built %s, want %s", s, want)
+	}
+}
+
+func TestValidateRequiresReference1(t *testing.T) {
+	q, err := NewBillPayment(testBiller).Reference1("REF1").QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Merchant.ID.PromptPayBillPayment.Reference1 = ""
+	found := false
+	for _, issue := range qr.Validate(q).Errors() {
+		found = found || issue.Tag == "30.02"
+	}
+	if !found {
+		t.Error("Validate accepts a bill payment without Reference 1")
+	}
+}
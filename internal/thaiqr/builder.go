@@ -0,0 +1,92 @@
+package thaiqr
+
+import (
+	"unicode/utf8"
+
+	"thaiqr-go/internal/qr"
+)
+
+// builder holds the QR and the first error of PromptPay, BillPayment and
+// Merchant, which wrap its setters to return themselves
+type builder struct {
+	qr    qr.QR
+	err   error
+	check func(*qr.QR) error // checks of the builder run by QR, nil for none
+}
+
+// QR returns the QR struct Build encodes, the mandatory tags are filled in.
+func (b *builder) QR() (*qr.QR, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.check != nil {
+		if err := b.check(&b.qr); err != nil {
+			return nil, err
+		}
+	}
+	q := b.qr
+	q.Merchant.ID.Schemes = append([]qr.SchemeAccount(nil), b.qr.Merchant.ID.Schemes...)
+	q.Merchant.ID.Accounts = append([]qr.Template(nil), b.qr.Merchant.ID.Accounts...)
+	fillMandatory(&q)
+	return &q, nil
+}
+
+// Build returns the encoded QR string
+func (b *builder) Build() (string, error) {
+	q, err := b.QR()
+	if err != nil {
+		return "", err
+	}
+	return encode(q)
+}
+
+// setAmount sets the amount in baht, such as "100" or "99.50"
+func (b *builder) setAmount(amount string) {
+	v, err := qr.ParseAmount(amount, 2)
+	if err != nil || v.IsZero() {
+		b.fail(&BuildError{Field: "amount", Value: amount, Err: ErrInvalidAmount})
+	}
+	b.qr.Transaction.Amount = v
+}
+
+// setReference sets the Reference ID of the Additional Data (62.05)
+func (b *builder) setReference(ref string) {
+	if utf8.RuneCountInString(ref) > 25 {
+		b.fail(&BuildError{Field: "reference", Value: ref, Err: ErrInvalidReference})
+	}
+	b.qr.AdditionalData.ReferenceID = ref
+}
+
+// setMerchant sets the merchant category code, name and city (52, 59, 60)
+func (b *builder) setMerchant(categoryCode, name, city string) {
+	b.qr.Merchant.CategoryCode = categoryCode
+	b.qr.Merchant.Name = name
+	b.qr.Merchant.City = city
+}
+
+func (b *builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// fillMandatory sets the payload format, currency, country and picks static
+// or dynamic initiation from the amount
+func fillMandatory(q *qr.QR) {
+	q.PayloadFormatIndicator = "01"
+	q.Transaction.CurrencyCode = CurrencyTHB
+	q.CountryCode = CountryThailand
+	q.PointOfInitiationMethod = q.InitiationMethod()
+}
+
+func encode(q *qr.QR) (string, error) {
+	m, err := qr.ConvertQRToMap(q)
+	if err != nil {
+		return "", &BuildError{Field: "qr", Err: err}
+	}
+	s, err := qr.ConvertMapToString(m)
+	if err != nil {
+		return "", &BuildError{Field: "qr", Err: err}
+	}
+	return s, nil
+}
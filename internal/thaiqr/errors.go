@@ -0,0 +1,35 @@
+package thaiqr
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors wrapped by BuildError, use errors.Is to check them
+var (
+	ErrInvalidProxy     = errors.New("invalid PromptPay proxy")
+	ErrInvalidAmount    = errors.New("invalid amount")
+	ErrInvalidReference = errors.New("invalid reference")
+	ErrMissingReference = errors.New("missing reference")
+	ErrInvalidBiller    = errors.New("invalid biller ID")
+	ErrInvalidAccount   = errors.New("invalid merchant account")
+	ErrTooManyAccounts  = errors.New("no free tag left for the merchant account")
+)
+
+// BuildError reports the field a builder could not accept
+type BuildError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *BuildError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("thaiqr: %s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("thaiqr: %s %q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *BuildError) Unwrap() error {
+	return e.Err
+}
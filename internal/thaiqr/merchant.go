@@ -0,0 +1,90 @@
+package thaiqr
+
+import (
+	"thaiqr-go/internal/qr"
+)
+
+// Merchant builds a merchant QR carrying several accounts, such as PromptPay,
+// Visa, Mastercard and UnionPay. Accounts are added in priority order: the
+// first account of a card scheme takes its primary tag (02 for Visa), the
+// next ones the following tags of its range (03).
+// Errors found while setting fields are kept and returned by Build.
+type Merchant struct {
+	builder
+}
+
+// NewMerchant starts a merchant QR with the merchant category code, name and city (52, 59, 60)
+func NewMerchant(categoryCode, name, city string) *Merchant {
+	b := &Merchant{}
+	b.setMerchant(categoryCode, name, city)
+	return b
+}
+
+// PromptPay adds a PromptPay credit transfer account (tag 29) to the given proxy, see NewPromptPay
+func (b *Merchant) PromptPay(id string) *Merchant {
+	if b.qr.Merchant.ID.PromptPay != (qr.QRMerchantIDPromptPay{}) {
+		b.fail(&BuildError{Field: "PromptPay account", Value: id, Err: ErrTooManyAccounts})
+		return b
+	}
+	pp := NewPromptPay(id)
+	if pp.err != nil {
+		b.fail(pp.err)
+		return b
+	}
+	b.qr.Merchant.ID.PromptPay = pp.qr.Merchant.ID.PromptPay
+	return b
+}
+
+// Card adds the merchant account of a card scheme at the next free tag of its range.
+// An all digits account of a scheme using PANs, such as Visa, must pass the Luhn check.
+func (b *Merchant) Card(scheme qr.Scheme, account string) *Merchant {
+	tags := qr.SchemeTags(scheme)
+	if len(tags) == 0 || account == "" {
+		b.fail(&BuildError{Field: string(scheme) + " account", Value: account, Err: ErrInvalidAccount})
+		return b
+	}
+	if scheme.UsesPAN() && qr.IsDigits(account) && !qr.Luhn(account) {
+		b.fail(&BuildError{Field: string(scheme) + " account", Value: account, Err: ErrInvalidAccount})
+		return b
+	}
+	for _, tag := range tags {
+		if !b.cardTagUsed(tag) {
+			b.qr.Merchant.ID.Schemes = append(b.qr.Merchant.ID.Schemes, qr.SchemeAccount{Tag: tag, Scheme: scheme, Value: account})
+			return b
+		}
+	}
+	b.fail(&BuildError{Field: string(scheme) + " account", Value: account, Err: ErrTooManyAccounts})
+	return b
+}
+
+// Account adds a merchant account template at the next free tag from 26, such
+// as an acquirer template registered with qr.RegisterTemplate
+func (b *Merchant) Account(t qr.Template) *Merchant {
+	if t.GUID == "" {
+		b.fail(&BuildError{Field: "merchant account template", Err: ErrInvalidAccount})
+		return b
+	}
+	b.qr.Merchant.ID.Accounts = append(b.qr.Merchant.ID.Accounts, t)
+	return b
+}
+
+// Amount sets the amount in baht, such as "100" or "99.50". A QR with an amount is dynamic.
+func (b *Merchant) Amount(amount string) *Merchant {
+	b.setAmount(amount)
+	return b
+}
+
+// Reference sets the Reference ID of the Additional Data (62.05)
+func (b *Merchant) Reference(ref string) *Merchant {
+	b.setReference(ref)
+	return b
+}
+
+func (b *Merchant) cardTagUsed(tag string) bool {
+	for _, a := range b.qr.Merchant.ID.Schemes {
+		if a.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
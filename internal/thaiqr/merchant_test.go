@@ -0,0 +1,100 @@
+package thaiqr
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"thaiqr-go/internal/qr"
+)
+
+func TestMerchant(t *testing.T) {
+	acquirer := qr.Template{GUID: "ID.CO.ACQUIRER.WWW", Fields: map[string]string{"01": "M123"}}
+	tests := []struct {
+		name     string
+		b        *Merchant
+		schemes  []string // tags of the card scheme accounts
+		template string   // tag of the acquirer template
+		err      error
+	}{
+		{"PromptPay and cards", NewMerchant("5814", "Cafe", "Bangkok").
+			PromptPay("0812345678").
+			Card(qr.SchemeVisa, "4111111111111111").
+			Card(qr.SchemeMastercard, "5555555555554444").
+			Card(qr.SchemeUnionPay, "6200000000000005"),
+			[]string{"02", "04", "15"}, "", nil},
+		{"second Visa account", NewMerchant("5814", "Cafe", "Bangkok").
+			Card(qr.SchemeVisa, "4111111111111111").
+			Card(qr.SchemeVisa, "4012888888881881"),
+			[]string{"02", "03"}, "", nil},
+		{"scheme specific Visa ID", NewMerchant("5814", "Cafe", "Bangkok").
+			Card(qr.SchemeVisa, "VISA-MERCHANT-1"),
+			[]string{"02"}, "", nil},
+		{"acquirer template", NewMerchant("5814", "Cafe", "Bangkok").
+			Account(acquirer).Amount("42.00").Reference("INV1"),
+			nil, "26", nil},
+		{"third Visa account", NewMerchant("5814", "Cafe", "Bangkok").
+			Card(qr.SchemeVisa, "4111111111111111").
+			Card(qr.SchemeVisa, "4012888888881881").
+			Card(qr.SchemeVisa, "4222222222222"),
+			nil, "", ErrTooManyAccounts},
+		{"Luhn", NewMerchant("5814", "Cafe", "Bangkok").Card(qr.SchemeVisa, "4111111111111112"), nil, "", ErrInvalidAccount},
+		{"unknown scheme", NewMerchant("5814", "Cafe", "Bangkok").Card(qr.Scheme("Diners"), "36227206271667"), nil, "", ErrInvalidAccount},
+		{"empty account", NewMerchant("5814", "Cafe", "Bangkok").Card(qr.SchemeJCB, ""), nil, "", ErrInvalidAccount},
+		{"template without GUID", NewMerchant("5814", "Cafe", "Bangkok").Account(qr.Template{}), nil, "", ErrInvalidAccount},
+		{"second PromptPay account", NewMerchant("5814", "Cafe", "Bangkok").PromptPay("0812345678").PromptPay("0899999999"), nil, "", ErrTooManyAccounts},
+		{"bad PromptPay proxy", NewMerchant("5814", "Cafe", "Bangkok").PromptPay("12345"), nil, "", ErrInvalidProxy},
+		{"bad amount", NewMerchant("5814", "Cafe", "Bangkok").PromptPay("0812345678").Amount("-1"), nil, "", ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		s, err := tt.b.Build()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		q, err := qr.DecodeQRVisa(s)
+		if err != nil {
+			t.Errorf("%s: %s does not decode: %v", tt.name, s, err)
+			continue
+		}
+		if q.Merchant.CategoryCode != "5814" || q.Merchant.Name != "Cafe" || q.Merchant.City != "Bangkok" {
+			t.Errorf("%s: decoded merchant %+v", tt.name, q.Merchant)
+		}
+		m, err := qr.ConvertQRToMap(q)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var tags []string
+		for _, tag := range append(qr.SchemeTags(qr.SchemeVisa), append(qr.SchemeTags(qr.SchemeMastercard), qr.SchemeTags(qr.SchemeUnionPay)...)...) {
+			if m[tag] != "" {
+				tags = append(tags, tag)
+			}
+		}
+		if !reflect.DeepEqual(tags, tt.schemes) {
+			t.Errorf("%s: card scheme tags %v, want %v", tt.name, tags, tt.schemes)
+		}
+		if tt.template != "" && m[tt.template] != "0018ID.CO.ACQUIRER.WWW0104M123" {
+			t.Errorf("%s: template %s = %q, want the acquirer template", tt.name, tt.template, m[tt.template])
+		}
+		for _, issue := range qr.Validate(q).Errors() {
+			t.Errorf("%s: %s: %v", tt.name, s, issue)
+		}
+	}
+}
+
+func TestMerchantQRIsACopy(t *testing.T) {
+	b := NewMerchant("5814", "Cafe", "Bangkok").Card(qr.SchemeVisa, "VISA-MERCHANT-1")
+	q, err := b.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q.Merchant.ID.Schemes[0].Value = "changed"
+	b.Card(qr.SchemeVisa, "VISA-MERCHANT-2")
+	if q2, _ := b.QR(); q2.Merchant.ID.Schemes[0].Value != "VISA-MERCHANT-1" || len(q.Merchant.ID.Schemes) != 1 {
+		t.Errorf("QR() shares the accounts of the builder: %+v", q2.Merchant.ID.Schemes)
+	}
+}
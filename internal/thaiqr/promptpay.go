@@ -0,0 +1,58 @@
+package thaiqr
+
+import (
+	"thaiqr-go/internal/qr"
+)
+
+const (
+	AIDPromptPay    = qr.GUIDPromptPay
+	AIDBillPayment  = qr.GUIDBillPayment
+	CurrencyTHB     = "764"
+	CountryThailand = "TH"
+)
+
+// PromptPay builds a PromptPay credit transfer QR (tag 29).
+// Errors found while setting fields are kept and returned by Build.
+type PromptPay struct {
+	builder
+}
+
+// NewPromptPay starts a PromptPay QR to the given proxy: a Thai mobile
+// number, a 13 digits national ID or tax ID, or a 15 digits e-wallet ID.
+// Dashes and spaces are ignored, see qr.DetectProxy.
+func NewPromptPay(id string) *PromptPay {
+	b := &PromptPay{}
+	b.qr.Merchant.ID.PromptPay.AID = AIDPromptPay
+	kind, proxy, err := qr.DetectProxy(id)
+	if err != nil {
+		b.err = &BuildError{Field: "proxy", Value: id, Err: ErrInvalidProxy}
+		return b
+	}
+	switch kind {
+	case qr.ProxyMobile:
+		b.qr.Merchant.ID.PromptPay.MobileNumber = proxy
+	case qr.ProxyNationalID:
+		b.qr.Merchant.ID.PromptPay.NationalID = proxy
+	case qr.ProxyEWallet:
+		b.qr.Merchant.ID.PromptPay.EWalletID = proxy
+	}
+	return b
+}
+
+// Amount sets the amount in baht, such as "100" or "99.50". A QR with an amount is dynamic.
+func (b *PromptPay) Amount(amount string) *PromptPay {
+	b.setAmount(amount)
+	return b
+}
+
+// Reference sets the Reference ID of the Additional Data (62.05)
+func (b *PromptPay) Reference(ref string) *PromptPay {
+	b.setReference(ref)
+	return b
+}
+
+// Merchant sets the merchant category code, name and city (52, 59, 60)
+func (b *PromptPay) Merchant(categoryCode, name, city string) *PromptPay {
+	b.setMerchant(categoryCode, name, city)
+	return b
+}
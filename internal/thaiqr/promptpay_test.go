@@ -0,0 +1,59 @@
+package thaiqr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPromptPayReferenceLength(t *testing.T) {
+	tests := []struct {
+		ref string
+		err error
+	}{
+		{strings.Repeat("ก", 25), nil},
+		{strings.Repeat("ก", 26), ErrInvalidReference},
+		{strings.Repeat("A", 25), nil},
+		{strings.Repeat("A", 26), ErrInvalidReference},
+	}
+	for _, tt := range tests {
+		if _, err := NewPromptPay("0812345678").Reference(tt.ref).Build(); !errors.Is(err, tt.err) {
+			t.Errorf("%d characters %q: got error %v, want %v", len([]rune(tt.ref)), tt.ref[:3], err, tt.err)
+		}
+	}
+}
+
+func TestPromptPay(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *PromptPay
+		want string
+		err  error
+	}{
+		{"mobile", NewPromptPay("081-234-5678"),
+			"00020101021129370016A0000006770101110113006681234567853037645802TH6304823E", nil},
+		{"national ID", NewPromptPay("1-1014-00188-17-0"),
+			"00020101021129370016A0000006770101110213110140018817053037645802TH6304F2A8", nil},
+		{"e-wallet with amount", NewPromptPay("004999000288505").Amount("100.50"),
+			"00020101021229390016A000000677010111031500499900028850553037645406100.505802TH6304DAE4", nil},
+		{"merchant and reference", NewPromptPay("0812345678").Merchant("5814", "Cafe", "Bangkok").Reference("INV1"),
+			"00020101021129370016A000000677010111011300668123456785204581453037645802TH5904Cafe6007Bangkok62080504INV163040328", nil},
+		{"bad proxy", NewPromptPay("12345"), "", ErrInvalidProxy},
+		{"bad proxy kept over a bad amount", NewPromptPay("12345").Amount("x"), "", ErrInvalidProxy},
+		{"bad amount", NewPromptPay("0812345678").Amount("1.234"), "", ErrInvalidAmount},
+		{"zero amount", NewPromptPay("0812345678").Amount("0.00"), "", ErrInvalidAmount},
+	}
+	for _, tt := range tests {
+		s, err := tt.b.Build()
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if s != tt.want {
+			t.Errorf("%s: built %s, want %s", tt.name, s, tt.want)
+		}
+	}
+}